@@ -52,6 +52,7 @@ func (c *alternativeClass_) FromFactors(factors col.Sequential[FactorLike]) Alte
 type alternative_ struct {
 	factors col.Sequential[FactorLike]
 	note    string
+	span    SpanLike
 }
 
 // Public Interface
@@ -64,6 +65,10 @@ func (v *alternative_) GetNote() string {
 	return v.note
 }
 
+func (v *alternative_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *alternative_) SetFactors(factors col.Sequential[FactorLike]) {
 	if factors == nil || factors.IsEmpty() {
 		panic("An alternative must have at least one factor.")
@@ -78,3 +83,7 @@ func (v *alternative_) SetNote(note string) {
 	}
 	v.note = note
 }
+
+func (v *alternative_) SetSpan(span SpanLike) {
+	v.span = span
+}
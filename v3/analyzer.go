@@ -0,0 +1,546 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type analyzerClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var analyzerClass = &analyzerClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func AnalyzerClass() AnalyzerClassLike {
+	return analyzerClass
+}
+
+// Public Class Constructors
+
+func (c *analyzerClass_) FromDocument(document DocumentLike) AnalyzerLike {
+	var analyzer = &analyzer_{
+		conflicts:   col.ListClass[DiagnosticLike]().Empty(),
+		definitions: col.CatalogClass[string, DefinitionLike]().Empty(),
+		diagnostics: col.ListClass[DiagnosticLike]().Empty(),
+		first:       col.CatalogClass[string, col.SetLike[string]]().Empty(),
+		follow:      col.CatalogClass[string, col.SetLike[string]]().Empty(),
+		leftCalls:   col.CatalogClass[string, col.ListLike[string]]().Empty(),
+		nullable:    col.CatalogClass[string, bool]().Empty(),
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil || definition.GetExpression() == nil {
+			continue
+		}
+		var symbol = definition.GetSymbol()
+		if isRuleName(symbol[1:]) && analyzer.definitions.GetValue(symbol) == nil {
+			analyzer.definitions.SetValue(symbol, definition)
+			analyzer.first.SetValue(symbol, col.SetClass[string]().Empty())
+			analyzer.follow.SetValue(symbol, col.SetClass[string]().Empty())
+		}
+	}
+	analyzer.analyzeNullability()
+	analyzer.analyzeLeftCalls()
+	analyzer.analyzeLeftRecursion()
+	analyzer.analyzeFirstSets()
+	analyzer.analyzeFollowSets()
+	analyzer.analyzeConflicts()
+	return analyzer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type analyzer_ struct {
+	conflicts   col.ListLike[DiagnosticLike]
+	definitions col.CatalogLike[string, DefinitionLike] // The rule definitions.
+	diagnostics col.ListLike[DiagnosticLike]
+	first       col.CatalogLike[string, col.SetLike[string]]
+	follow      col.CatalogLike[string, col.SetLike[string]]
+	leftCalls   col.CatalogLike[string, col.ListLike[string]]
+	nullable    col.CatalogLike[string, bool]
+	symbol      string // The symbol of the rule definition being analyzed.
+}
+
+// Public Interface
+
+func (v *analyzer_) GetConflicts() col.Sequential[DiagnosticLike] {
+	return v.conflicts
+}
+
+func (v *analyzer_) GetDiagnostics() col.Sequential[DiagnosticLike] {
+	return v.diagnostics
+}
+
+func (v *analyzer_) GetFirstSet(symbol string) col.Sequential[string] {
+	return v.first.GetValue(symbol)
+}
+
+func (v *analyzer_) GetFollowSet(symbol string) col.Sequential[string] {
+	return v.follow.GetValue(symbol)
+}
+
+func (v *analyzer_) GetLeftRecursion(symbol string) col.Sequential[string] {
+	var cycle = v.findLeftCycle(symbol)
+	if cycle == nil {
+		return nil
+	}
+	return col.ListClass[string]().FromArray(cycle)
+}
+
+func (v *analyzer_) IsNullable(symbol string) bool {
+	return v.nullable.GetValue(symbol)
+}
+
+// Private Interface
+
+// This private class method reports each choice within the rule definitions
+// that cannot be made by looking at the next token only.
+func (v *analyzer_) analyzeConflicts() {
+	var symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		v.symbol = symbols.GetNext()
+		var expression = v.definitions.GetValue(v.symbol).GetExpression()
+		v.walkExpression(expression, v.follow.GetValue(v.symbol), v.checkConflicts)
+	}
+}
+
+// This private class method calculates the set of terminals that may begin
+// each rule definition.  The sets are recalculated until nothing changes.
+func (v *analyzer_) analyzeFirstSets() {
+	var changed = true
+	for changed {
+		changed = false
+		var symbols = v.definitions.GetKeys().GetIterator()
+		for symbols.HasNext() {
+			var symbol = symbols.GetNext()
+			var first = v.first.GetValue(symbol)
+			var size = first.GetSize()
+			var expression = v.definitions.GetValue(symbol).GetExpression()
+			first.AddValues(v.firstOfExpression(expression))
+			changed = changed || first.GetSize() > size
+		}
+	}
+}
+
+// This private class method calculates the set of terminals that may follow
+// each rule definition.  The sets are recalculated until nothing changes.
+func (v *analyzer_) analyzeFollowSets() {
+	var changed = true
+	for changed {
+		changed = false
+		var symbols = v.definitions.GetKeys().GetIterator()
+		for symbols.HasNext() {
+			v.symbol = symbols.GetNext()
+			var expression = v.definitions.GetValue(v.symbol).GetExpression()
+			v.walkExpression(
+				expression,
+				v.follow.GetValue(v.symbol),
+				func(expression ExpressionLike, factor FactorLike, follow col.SetLike[string]) {
+					if factor == nil {
+						return
+					}
+					var element = factor.GetPredicate().GetAssertion().GetElement()
+					if factor.GetPredicate().IsInverted() || element == nil ||
+						!isRuleName(element.GetName()) {
+						return
+					}
+					var set = v.follow.GetValue("$" + element.GetName())
+					if set != nil {
+						var size = set.GetSize()
+						set.AddValues(follow)
+						var _, maximum = getLimits(factor.GetCardinality())
+						if maximum < 0 || maximum > 1 {
+							// The rule may be followed by itself.
+							set.AddValues(v.firstOfFactor(factor))
+						}
+						changed = changed || set.GetSize() > size
+					}
+				},
+			)
+		}
+	}
+}
+
+// This private class method determines for each rule definition which rules
+// may be parsed first, without consuming any tokens, when parsing the rule.
+func (v *analyzer_) analyzeLeftCalls() {
+	var symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		var calls = col.ListClass[string]().Empty()
+		var expression = v.definitions.GetValue(symbol).GetExpression()
+		v.collectLeftCalls(expression, calls)
+		v.leftCalls.SetValue(symbol, calls)
+	}
+}
+
+// This private class method reports each rule definition that is directly or
+// indirectly left recursive along with the shortest left recursive cycle.
+func (v *analyzer_) analyzeLeftRecursion() {
+	var symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		var cycle = v.findLeftCycle(symbol)
+		if cycle == nil {
+			continue
+		}
+		var definition = v.definitions.GetValue(symbol)
+		var diagnostic = DiagnosticClass().FromKind(
+			DiagnosticClass().GetLeftRecursive(),
+			DiagnosticClass().GetWarning(),
+			symbol,
+			"A rule definition cannot be left recursive: "+sts.Join(cycle, " → "),
+			definition.GetSpan(),
+		)
+		v.diagnostics.AppendValue(diagnostic)
+	}
+}
+
+// This private class method determines which rule definitions can match
+// without consuming any tokens.  The nullability of each rule is recalculated
+// until nothing changes.
+func (v *analyzer_) analyzeNullability() {
+	var changed = true
+	for changed {
+		changed = false
+		var symbols = v.definitions.GetKeys().GetIterator()
+		for symbols.HasNext() {
+			var symbol = symbols.GetNext()
+			if v.nullable.GetValue(symbol) {
+				continue
+			}
+			var expression = v.definitions.GetValue(symbol).GetExpression()
+			if v.isNullableExpression(expression) {
+				v.nullable.SetValue(symbol, true)
+				changed = true
+			}
+		}
+	}
+}
+
+// This private class method checks the specified expression, or optional or
+// repeated factor, for a choice that cannot be made using the next token.  The
+// follow set contains the terminals that may follow it.
+func (v *analyzer_) checkConflicts(
+	expression ExpressionLike,
+	factor FactorLike,
+	follow col.SetLike[string],
+) {
+	if factor != nil {
+		var minimum, maximum = getLimits(factor.GetCardinality())
+		if minimum == maximum {
+			return
+		}
+		var overlap = col.SetClass[string]().And(v.firstOfFactor(factor), follow)
+		if !overlap.IsEmpty() {
+			v.reportConflict(
+				factor.GetSpan(),
+				"The optional or repeated factor begins with a terminal that may also follow it: "+
+					sts.Join(overlap.AsArray(), ", "),
+			)
+		}
+		return
+	}
+	var alternatives = expression.GetAlternatives().AsArray()
+	for i, first := range alternatives {
+		for j := i + 1; j < len(alternatives); j++ {
+			var second = alternatives[j]
+			var overlap = col.SetClass[string]().And(
+				v.firstOfAlternative(first),
+				v.firstOfAlternative(second),
+			)
+			if !overlap.IsEmpty() {
+				v.reportConflict(
+					expression.GetSpan(),
+					fmt.Sprintf("The alternatives %v and %v both begin with: %v",
+						i+1, j+1, sts.Join(overlap.AsArray(), ", ")),
+				)
+			}
+			for _, pair := range [][2]int{{i, j}, {j, i}} {
+				if !v.isNullableAlternative(alternatives[pair[0]]) {
+					continue
+				}
+				overlap = col.SetClass[string]().And(
+					v.firstOfAlternative(alternatives[pair[1]]),
+					follow,
+				)
+				if !overlap.IsEmpty() {
+					v.reportConflict(
+						expression.GetSpan(),
+						fmt.Sprintf("The alternative %v can be empty but alternative %v begins with a terminal that may follow it: %v",
+							pair[0]+1, pair[1]+1, sts.Join(overlap.AsArray(), ", ")),
+					)
+				}
+			}
+		}
+	}
+}
+
+// This private class method adds to the specified list the rules that may be
+// parsed first when parsing the specified expression.
+func (v *analyzer_) collectLeftCalls(
+	expression ExpressionLike,
+	calls col.ListLike[string],
+) {
+	if expression == nil || expression.GetAlternatives() == nil {
+		return
+	}
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var factors = alternatives.GetNext().GetFactors()
+		if factors == nil {
+			continue
+		}
+		var iterator = factors.GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			var predicate = factor.GetPredicate()
+			if predicate == nil || predicate.IsInverted() || predicate.GetAssertion() == nil {
+				break
+			}
+			var assertion = predicate.GetAssertion()
+			var element = assertion.GetElement()
+			var precedence = assertion.GetPrecedence()
+			switch {
+			case precedence != nil:
+				v.collectLeftCalls(precedence.GetExpression(), calls)
+			case element != nil && isRuleName(element.GetName()):
+				calls.AppendValue("$" + element.GetName())
+			}
+			if !v.isNullableFactor(factor) {
+				break
+			}
+		}
+	}
+}
+
+// This private class method returns the shortest path of left calls that leads
+// from the specified rule definition back to itself, or nil if there is no such
+// path.
+func (v *analyzer_) findLeftCycle(symbol string) []string {
+	var previous = col.CatalogClass[string, string]().Empty()
+	var pending = col.ListClass[string]().FromArray([]string{symbol})
+	for !pending.IsEmpty() {
+		var current = pending.RemoveValue(1)
+		var calls = v.leftCalls.GetValue(current)
+		if calls == nil {
+			continue
+		}
+		var iterator = calls.GetIterator()
+		for iterator.HasNext() {
+			var called = iterator.GetNext()
+			if len(previous.GetValue(called)) > 0 {
+				continue
+			}
+			previous.SetValue(called, current)
+			if called == symbol {
+				// Walk the path backwards to recover the cycle.
+				var cycle = []string{symbol}
+				for current != symbol {
+					cycle = append([]string{current}, cycle...)
+					current = previous.GetValue(current)
+				}
+				return append([]string{symbol}, cycle...)
+			}
+			pending.AppendValue(called)
+		}
+	}
+	return nil
+}
+
+func (v *analyzer_) firstOfAlternative(alternative AlternativeLike) col.SetLike[string] {
+	var first = col.SetClass[string]().Empty()
+	var factors = alternative.GetFactors()
+	if factors == nil {
+		return first
+	}
+	var iterator = factors.GetIterator()
+	for iterator.HasNext() {
+		var factor = iterator.GetNext()
+		first.AddValues(v.firstOfFactor(factor))
+		if !v.isNullableFactor(factor) {
+			break
+		}
+	}
+	return first
+}
+
+func (v *analyzer_) firstOfExpression(expression ExpressionLike) col.SetLike[string] {
+	var first = col.SetClass[string]().Empty()
+	if expression == nil || expression.GetAlternatives() == nil {
+		return first
+	}
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		first.AddValues(v.firstOfAlternative(alternative))
+	}
+	return first
+}
+
+// This private class method returns the set of terminals that may begin the
+// specified factor.  An inverted predicate begins with any terminal other than
+// the ones it inverts, it is represented by the inverted terminals.
+func (v *analyzer_) firstOfFactor(factor FactorLike) col.SetLike[string] {
+	var first = col.SetClass[string]().Empty()
+	var predicate = factor.GetPredicate()
+	if predicate == nil || predicate.GetAssertion() == nil {
+		return first
+	}
+	var assertion = predicate.GetAssertion()
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case predicate.IsInverted():
+		first.AddValue("~" + describeAssertion(assertion))
+	case precedence != nil:
+		first.AddValues(v.firstOfExpression(precedence.GetExpression()))
+	case element != nil && isRuleName(element.GetName()):
+		var set = v.first.GetValue("$" + element.GetName())
+		if set != nil {
+			first.AddValues(set)
+		}
+	default:
+		first.AddValue(describeAssertion(assertion))
+	}
+	return first
+}
+
+func (v *analyzer_) isNullableAlternative(alternative AlternativeLike) bool {
+	var factors = alternative.GetFactors()
+	if factors == nil {
+		return false
+	}
+	var iterator = factors.GetIterator()
+	for iterator.HasNext() {
+		var factor = iterator.GetNext()
+		if !v.isNullableFactor(factor) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *analyzer_) isNullableExpression(expression ExpressionLike) bool {
+	if expression == nil || expression.GetAlternatives() == nil {
+		return false
+	}
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		if v.isNullableAlternative(alternative) {
+			return true
+		}
+	}
+	return false
+}
+
+// This private class method determines whether or not the specified factor can
+// match without consuming any tokens.  Terminals always consume a token.
+func (v *analyzer_) isNullableFactor(factor FactorLike) bool {
+	var cardinality = factor.GetCardinality()
+	if cardinality != nil && cardinality.GetConstraint() != nil {
+		var minimum, _ = getLimits(cardinality)
+		if minimum == 0 {
+			return true
+		}
+	}
+	var predicate = factor.GetPredicate()
+	if predicate == nil || predicate.IsInverted() || predicate.GetAssertion() == nil {
+		return false
+	}
+	var assertion = predicate.GetAssertion()
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case precedence != nil:
+		return v.isNullableExpression(precedence.GetExpression())
+	case element != nil && isRuleName(element.GetName()):
+		return v.nullable.GetValue("$" + element.GetName())
+	default:
+		return false
+	}
+}
+
+// This private class method reports a choice that cannot be made using the
+// next token within the current rule definition.
+func (v *analyzer_) reportConflict(span SpanLike, message string) {
+	var diagnostic = DiagnosticClass().FromKind(
+		DiagnosticClass().GetConflict(),
+		DiagnosticClass().GetWarning(),
+		v.symbol,
+		message,
+		span,
+	)
+	v.conflicts.AppendValue(diagnostic)
+}
+
+// This private class method walks the specified expression passing each
+// expression, and each factor, to the specified function along with the set of
+// terminals that may follow it.
+func (v *analyzer_) walkExpression(
+	expression ExpressionLike,
+	follow col.SetLike[string],
+	function func(expression ExpressionLike, factor FactorLike, follow col.SetLike[string]),
+) {
+	if expression == nil || expression.GetAlternatives() == nil {
+		return
+	}
+	function(expression, nil, follow)
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var factors = alternatives.GetNext().GetFactors()
+		if factors == nil {
+			continue
+		}
+
+		// Walk the factors backwards accumulating what may follow each one.
+		var array = factors.AsArray()
+		var tail = col.SetClass[string]().FromSequence(follow)
+		for index := len(array) - 1; index >= 0; index-- {
+			var factor = array[index]
+			if factor.GetPredicate() == nil || factor.GetPredicate().GetAssertion() == nil {
+				continue
+			}
+			function(nil, factor, tail)
+			var after = col.SetClass[string]().FromSequence(tail)
+			var _, maximum = getLimits(factor.GetCardinality())
+			if maximum < 0 || maximum > 1 {
+				// The contents of the factor may be followed by the factor itself.
+				after.AddValues(v.firstOfFactor(factor))
+			}
+			var precedence = factor.GetPredicate().GetAssertion().GetPrecedence()
+			if precedence != nil && !factor.GetPredicate().IsInverted() {
+				v.walkExpression(precedence.GetExpression(), after, function)
+			}
+			if !v.isNullableFactor(factor) {
+				tail = col.SetClass[string]().Empty()
+			}
+			tail.AddValues(v.firstOfFactor(factor))
+		}
+	}
+}
@@ -0,0 +1,431 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type antlrClass_ struct {
+	classes    map[string]string
+	intrinsics map[string]string
+	keywords   map[string]bool
+}
+
+// Private Class Namespace Reference
+
+var antlrClass = &antlrClass_{
+	// The ANTLR character class for each intrinsic that matches one character.
+	classes: map[string]string{
+		"CONTROL": `\p{Cc}`,
+		"DIGIT":   `\p{Nd}`,
+		"EOL":     `\n`,
+		"LOWER":   `\p{Ll}`,
+		"UPPER":   `\p{Lu}`,
+	},
+
+	// The ANTLR lexer rule for each intrinsic.
+	intrinsics: map[string]string{
+		"ANY":     `.`,
+		"CONTROL": `[\p{Cc}]`,
+		"DIGIT":   `[\p{Nd}]`,
+		"EOL":     `'\n'`,
+		"ESCAPE": `'\\' ([abfnrtv'"\\] | 'x' [0-9a-f] [0-9a-f]` +
+			` | 'u' [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f]` +
+			` | 'U' [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f])`,
+		"LOWER": `[\p{Ll}]`,
+		"UPPER": `[\p{Lu}]`,
+	},
+
+	// The ANTLR keywords that cannot be used as rule names.
+	keywords: map[string]bool{
+		"catch":    true,
+		"finally":  true,
+		"fragment": true,
+		"grammar":  true,
+		"import":   true,
+		"lexer":    true,
+		"locals":   true,
+		"mode":     true,
+		"options":  true,
+		"parser":   true,
+		"returns":  true,
+		"throws":   true,
+		"tokens":   true,
+	},
+}
+
+// Private Class Constructors
+
+// This private class constructor creates a new translator from the specified
+// document to an ANTLR4 combined grammar.  Token definitions become lexer rules
+// and rule definitions become parser rules.  The token definitions that are not
+// used by a rule definition become fragments.
+func (c *antlrClass_) fromDocument(document DocumentLike) *antlr_ {
+	var antlr = &antlr_{
+		definitions: make(map[string]DefinitionLike),
+		document:    document,
+		intrinsics:  col.SetClass[string]().Empty(),
+		lexerRules:  col.SetClass[string]().Empty(),
+		ranges:      col.ListClass[string]().Empty(),
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			antlr.definitions[definition.GetSymbol()[1:]] = definition
+		}
+	}
+	return antlr
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type antlr_ struct {
+	definitions map[string]DefinitionLike // The definitions keyed by name.
+	document    DocumentLike
+	intrinsics  col.SetLike[string]  // The intrinsics that are used.
+	isToken     bool                 // Whether or not a token is being translated.
+	lexerRules  col.SetLike[string]  // The tokens and intrinsics used by rules.
+	ranges      col.ListLike[string] // The glyph ranges used by rules.
+	visiting    map[string]bool      // The token names being inverted.
+}
+
+// Private Interface
+
+// This private class method returns the ANTLR4 combined grammar with the
+// specified name.
+func (v *antlr_) generateGrammar(name string) string {
+	var parserRules = make(map[string]string)
+	var iterator = v.document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil && isRuleName(definition.GetSymbol()[1:]) {
+			parserRules[definition.GetSymbol()] = v.translateDefinition(definition)
+		}
+	}
+	var builder sts.Builder
+	builder.WriteString("// This grammar was generated from a CDSN grammar.")
+	if parserRules[generatorClass.defaultSymbol] != "" {
+		builder.WriteString("  The start rule is document.")
+	}
+	builder.WriteString("\ngrammar " + name + ";\n")
+	iterator = v.document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		var comment = statement.GetComment()
+		var definition = statement.GetDefinition()
+		builder.WriteString("\n")
+		switch {
+		case len(comment) > 0:
+			var text = sts.TrimSuffix(sts.TrimPrefix(comment, "!>"), "<!")
+			builder.WriteString("/*" + sts.ReplaceAll(text, "*/", "* /") + "*/\n")
+		case isRuleName(definition.GetSymbol()[1:]):
+			builder.WriteString(parserRules[definition.GetSymbol()])
+		default:
+			builder.WriteString(v.translateDefinition(definition))
+		}
+	}
+	var ranges = v.ranges.GetIterator()
+	for ranges.HasNext() {
+		var rule = fmt.Sprintf("RANGE_%v_", ranges.GetSlot()+1)
+		builder.WriteString("\n" + rule + "\n    : " + ranges.GetNext() + "\n    ;\n")
+	}
+	var intrinsics = v.intrinsics.GetIterator()
+	for intrinsics.HasNext() {
+		var intrinsic = intrinsics.GetNext()
+		var rule = intrinsic
+		if !v.lexerRules.ContainsValue(intrinsic) {
+			rule = "fragment " + rule
+		}
+		builder.WriteString("\n" + rule + "\n    : " + antlrClass.intrinsics[intrinsic] + "\n    ;\n")
+	}
+	var terminal = v.spaceTerminal()
+	if len(terminal) > 0 {
+		// ANTLR would choose the terminal over a skipped space of equal length.
+		builder.WriteString("\n// Spaces between the tokens of a rule are NOT ignored since the\n")
+		builder.WriteString("// terminal " + terminal + " matches a single space.\n")
+		return builder.String()
+	}
+	builder.WriteString("\n// Spaces between the tokens of a rule are ignored.\n")
+	builder.WriteString("SPACES_\n    : ' '+ -> skip\n    ;\n")
+	return builder.String()
+}
+
+// This private class method returns the first terminal within the rule
+// definitions that matches a single space, or an empty string if there is none.
+func (v *antlr_) spaceTerminal() string {
+	var matcher = matcherClass.fromRunes(nil, v.definitions)
+	for _, terminal := range collectTerminals(v.document) {
+		if matcher.matchesRune(terminal.assertion, ' ') {
+			return terminal.name
+		}
+	}
+	return ""
+}
+
+func (v *antlr_) translateAlternative(alternative AlternativeLike) string {
+	var factors []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		var factor, _ = v.translateFactor(iterator.GetNext())
+		factors = append(factors, factor)
+	}
+	return sts.Join(factors, " ")
+}
+
+// This private class method returns the translated assertion and whether or
+// not it can be followed by a repetition operator without being grouped.
+func (v *antlr_) translateAssertion(assertion AssertionLike) (string, bool) {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.translateElement(element), true
+	case glyph != nil && len(glyph.GetLast()) == 0:
+		return quoteANTLR(string(unquoteCharacter(glyph.GetFirst()))), true
+	case glyph != nil && v.isToken:
+		return "[" + v.translateCharacters(assertion) + "]", true
+	case glyph != nil:
+		// A parser rule cannot contain a range of characters.
+		var characters = "[" + v.translateCharacters(assertion) + "]"
+		var index = 1
+		var ranges = v.ranges.GetIterator()
+		for ranges.HasNext() && ranges.GetNext() != characters {
+			index++
+		}
+		if index > v.ranges.GetSize() {
+			v.ranges.AppendValue(characters)
+		}
+		return fmt.Sprintf("RANGE_%v_", index), true
+	case precedence != nil:
+		return "(" + v.translateExpression(precedence.GetExpression()) + ")", true
+	default:
+		panic("Attempted to translate an empty assertion.")
+	}
+}
+
+// This private class method returns the body of an ANTLR character set that
+// matches the same characters as the specified assertion.
+func (v *antlr_) translateCharacters(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case glyph != nil:
+		var characters = quoteSetCharacter(unquoteCharacter(glyph.GetFirst()))
+		if len(glyph.GetLast()) > 0 {
+			characters += "-" + quoteSetCharacter(unquoteCharacter(glyph.GetLast()))
+		}
+		return characters
+	case precedence != nil:
+		var characters string
+		var alternatives = precedence.GetExpression().GetAlternatives().GetIterator()
+		for alternatives.HasNext() {
+			var factors = alternatives.GetNext().GetFactors()
+			var factor = factors.AsArray()[0]
+			if factors.GetSize() != 1 || factor.GetCardinality() != nil ||
+				factor.GetPredicate().IsInverted() {
+				panic("Only single characters can be inverted.")
+			}
+			characters += v.translateCharacters(factor.GetPredicate().GetAssertion())
+		}
+		return characters
+	}
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case len(antlrClass.classes[intrinsic]) > 0:
+		return antlrClass.classes[intrinsic]
+	case len(intrinsic) > 0:
+		panic(fmt.Sprintf("The intrinsic %v cannot be inverted.", intrinsic))
+	case len(literal) > 0:
+		var runes = []rune(unquoteLiteral(literal))
+		if len(runes) != 1 {
+			panic(fmt.Sprintf("The literal %v cannot be inverted.", literal))
+		}
+		return quoteSetCharacter(runes[0])
+	default:
+		if v.visiting[name] {
+			panic(fmt.Sprintf("The token %v is recursive.", name))
+		}
+		v.visiting[name] = true
+		var definition = v.definitions[name]
+		var precedence = PrecedenceClass().FromExpression(definition.GetExpression())
+		var characters = v.translateCharacters(AssertionClass().FromPrecedence(precedence))
+		delete(v.visiting, name)
+		return characters
+	}
+}
+
+// This private class method returns the translated definition.  Each
+// alternative is placed on its own line followed by its note, if any.
+func (v *antlr_) translateDefinition(definition DefinitionLike) string {
+	var name = definition.GetSymbol()[1:]
+	v.isToken = !isRuleName(name)
+	v.visiting = make(map[string]bool)
+	var rule = v.translateName(name)
+	if v.isToken && !v.lexerRules.ContainsValue(name) {
+		rule = "fragment " + rule
+	}
+	var lines []string
+	var iterator = definition.GetExpression().GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		var line = v.translateAlternative(alternative)
+		var note = alternative.GetNote()
+		if len(note) > 0 {
+			line += "  // " + sts.TrimPrefix(note, "! ")
+		}
+		lines = append(lines, line)
+	}
+	return rule + "\n    : " + sts.Join(lines, "\n    | ") + "\n    ;\n"
+}
+
+func (v *antlr_) translateElement(element ElementLike) string {
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case intrinsic == "EOF":
+		return "EOF"
+	case len(intrinsic) > 0:
+		v.intrinsics.AddValue(intrinsic)
+		if !v.isToken {
+			v.lexerRules.AddValue(intrinsic)
+		}
+		return intrinsic
+	case len(name) > 0:
+		return v.translateName(name)
+	case len(literal) > 0:
+		return quoteANTLR(unquoteLiteral(literal))
+	default:
+		panic("Attempted to translate an empty element.")
+	}
+}
+
+func (v *antlr_) translateExpression(expression ExpressionLike) string {
+	var alternatives []string
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		alternatives = append(alternatives, v.translateAlternative(iterator.GetNext()))
+	}
+	return sts.Join(alternatives, " | ")
+}
+
+// This private class method returns the translated factor and whether or not
+// it can be followed by a repetition operator without being grouped.  Counted
+// repetitions are expanded since ANTLR does not support them, and repetitions
+// of ANY are not greedy.
+func (v *antlr_) translateFactor(factor FactorLike) (string, bool) {
+	var predicate = factor.GetPredicate()
+	var text, isAtomic = v.translatePredicate(predicate)
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	if minimum == 1 && maximum == 1 {
+		return text, isAtomic
+	}
+	if !isAtomic {
+		text = "(" + text + ")"
+	}
+	text, isAtomic = repeatGroup(text, minimum, maximum)
+	var element = predicate.GetAssertion().GetElement()
+	if v.isToken && !predicate.IsInverted() && element != nil &&
+		element.GetIntrinsic() == "ANY" && minimum != maximum {
+		text += "?"
+	}
+	return text, isAtomic
+}
+
+// This private class method returns the name of the ANTLR rule for the
+// specified definition name.
+func (v *antlr_) translateName(name string) string {
+	if !isRuleName(name) {
+		if !v.isToken {
+			v.lexerRules.AddValue(name)
+		}
+		return name
+	}
+	if antlrClass.keywords[name] {
+		// Rule names cannot end with an underscore so this is unique.
+		return name + "_"
+	}
+	return name
+}
+
+// This private class method returns the translated predicate and whether or
+// not it can be followed by a repetition operator without being grouped.  An
+// inverted predicate within a token becomes a negated character set.
+func (v *antlr_) translatePredicate(predicate PredicateLike) (string, bool) {
+	var assertion = predicate.GetAssertion()
+	if !predicate.IsInverted() {
+		return v.translateAssertion(assertion)
+	}
+	if v.isToken {
+		return "~[" + v.translateCharacters(assertion) + "]", true
+	}
+	var text, _ = v.translateAssertion(assertion)
+	return "~" + text, true
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the specified text as an ANTLR string literal.
+func quoteANTLR(text string) string {
+	var builder sts.Builder
+	builder.WriteString("'")
+	for _, character := range text {
+		switch {
+		case character == '\'', character == '\\':
+			builder.WriteString(`\` + string(character))
+		case character == '\n':
+			builder.WriteString(`\n`)
+		case character == '\r':
+			builder.WriteString(`\r`)
+		case character == '\t':
+			builder.WriteString(`\t`)
+		case character < ' ' || character == 0x7f:
+			builder.WriteString(fmt.Sprintf(`\u%04X`, character))
+		default:
+			builder.WriteRune(character)
+		}
+	}
+	builder.WriteString("'")
+	return builder.String()
+}
+
+// This private function returns the specified character as it would appear
+// within an ANTLR character set.
+func quoteSetCharacter(character rune) string {
+	switch {
+	case sts.ContainsRune(`]\-`, character):
+		return `\` + string(character)
+	case character == '\n':
+		return `\n`
+	case character == '\r':
+		return `\r`
+	case character == '\t':
+		return `\t`
+	case character < ' ' || character == 0x7f:
+		return fmt.Sprintf(`\u%04X`, character)
+	default:
+		return string(character)
+	}
+}
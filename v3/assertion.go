@@ -64,6 +64,7 @@ type assertion_ struct {
 	element    ElementLike
 	glyph      GlyphLike
 	precedence PrecedenceLike
+	span       SpanLike
 }
 
 // Public Interface
@@ -80,6 +81,10 @@ func (v *assertion_) GetPrecedence() PrecedenceLike {
 	return v.precedence
 }
 
+func (v *assertion_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *assertion_) SetElement(element ElementLike) {
 	if element == nil {
 		panic("An element must not be nil.")
@@ -106,3 +111,7 @@ func (v *assertion_) SetPrecedence(precedence PrecedenceLike) {
 	v.glyph = nil
 	v.precedence = precedence
 }
+
+func (v *assertion_) SetSpan(span SpanLike) {
+	v.span = span
+}
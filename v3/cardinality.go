@@ -46,6 +46,7 @@ func (c *cardinalityClass_) FromConstraint(constraint ConstraintLike) Cardinalit
 
 type cardinality_ struct {
 	constraint ConstraintLike
+	span       SpanLike
 }
 
 // Public Interface
@@ -54,9 +55,17 @@ func (v *cardinality_) GetConstraint() ConstraintLike {
 	return v.constraint
 }
 
+func (v *cardinality_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *cardinality_) SetConstraint(constraint ConstraintLike) {
 	if constraint == nil {
 		panic("A constraint must not be nil.")
 	}
 	v.constraint = constraint
 }
+
+func (v *cardinality_) SetSpan(span SpanLike) {
+	v.span = span
+}
@@ -0,0 +1,102 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type changeClass_ struct {
+	added_    string
+	modified_ string
+	removed_  string
+}
+
+// Private Class Namespace Reference
+
+var changeClass = &changeClass_{
+	added_:    "Added",
+	modified_: "Modified",
+	removed_:  "Removed",
+}
+
+// Public Class Namespace Access
+
+func ChangeClass() ChangeClassLike {
+	return changeClass
+}
+
+// Public Class Constants
+
+func (c *changeClass_) GetAdded() string {
+	return c.added_
+}
+
+func (c *changeClass_) GetModified() string {
+	return c.modified_
+}
+
+func (c *changeClass_) GetRemoved() string {
+	return c.removed_
+}
+
+// Public Class Constructors
+
+func (c *changeClass_) FromKind(
+	kind string,
+	symbol string,
+	details col.Sequential[string],
+) ChangeLike {
+	var change = &change_{
+		details: details,
+		kind:    kind,
+		symbol:  symbol,
+	}
+	return change
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type change_ struct {
+	details col.Sequential[string] // Empty unless the definition was modified.
+	kind    string
+	symbol  string
+}
+
+// Public Interface
+
+func (v *change_) GetDetails() col.Sequential[string] {
+	return v.details
+}
+
+func (v *change_) GetKind() string {
+	return v.kind
+}
+
+func (v *change_) GetSymbol() string {
+	return v.symbol
+}
+
+func (v *change_) String() string {
+	var s = fmt.Sprintf("%v: %v", v.kind, v.symbol)
+	var iterator = v.details.GetIterator()
+	for iterator.HasNext() {
+		s += "\n    " + iterator.GetNext()
+	}
+	return s
+}
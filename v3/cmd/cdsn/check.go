@@ -0,0 +1,74 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package main
+
+import (
+	flg "flag"
+	fmt "fmt"
+	cds "github.com/craterdog/go-cdsn-validation/v3"
+)
+
+// This private function runs the check command, which parses and validates
+// each grammar and reports each problem that is found in the form
+// "file:line:column: severity: message".  The exit status is 1 if any errors
+// are found.  Warnings do not affect the exit status.
+func runCheck(arguments []string, environment *environment_) int {
+	var flags = flg.NewFlagSet("check", flg.ContinueOnError)
+	flags.SetOutput(environment.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(environment.stderr, "usage: cdsn check [-strict] [files]\n")
+		flags.PrintDefaults()
+	}
+	var strict = flags.Bool("strict", false, "report LL(1) conflicts as errors")
+	if flags.Parse(arguments) != nil {
+		return 2
+	}
+	var names, err = expandArguments(flags.Args())
+	if err != nil {
+		fmt.Fprintf(environment.stderr, "cdsn check: %v\n", err)
+		return 2
+	}
+
+	var status = 0
+	var parser = cds.ParserClass().Default()
+	var validator = cds.ValidatorClass().Default()
+	if *strict {
+		validator = cds.ValidatorClass().Strict()
+	}
+	for _, name := range names {
+		var source, err = readSource(name, environment)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v\n", err)
+			status = 1
+			continue
+		}
+		var document cds.DocumentLike
+		document, err = parser.ParseDocumentSafely(source)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v:%v\n", displayName(name), err)
+			status = 1
+			continue
+		}
+		var iterator = validator.DiagnoseDocument(document).GetIterator()
+		for iterator.HasNext() {
+			var diagnostic = iterator.GetNext()
+			var separator = ":"
+			if diagnostic.GetLine() == 0 {
+				separator = ": "
+			}
+			fmt.Fprintf(environment.stderr, "%v%v%v\n", displayName(name), separator, diagnostic)
+			if diagnostic.GetSeverity() == cds.DiagnosticClass().GetError() {
+				status = 1
+			}
+		}
+	}
+	return status
+}
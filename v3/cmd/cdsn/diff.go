@@ -0,0 +1,70 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package main
+
+import (
+	flg "flag"
+	fmt "fmt"
+	cds "github.com/craterdog/go-cdsn-validation/v3"
+)
+
+// This private function runs the diff command, which compares two versions of
+// a grammar and lists the definitions that were added, removed or modified
+// along with the structural changes to each modified definition.  Comments,
+// notes and formatting are ignored.  Like diff(1), the exit status is 0 if the
+// grammars define the same language structure, 1 if they differ and 2 if
+// either grammar cannot be read or parsed.
+func runDiff(arguments []string, environment *environment_) int {
+	var flags = flg.NewFlagSet("diff", flg.ContinueOnError)
+	flags.SetOutput(environment.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(environment.stderr, "usage: cdsn diff old new\n")
+		flags.PrintDefaults()
+	}
+	if flags.Parse(arguments) != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if flags.Arg(0) == "-" && flags.Arg(1) == "-" {
+		fmt.Fprintf(environment.stderr, "cdsn diff: cannot read both grammars from the standard input\n")
+		return 2
+	}
+
+	var parser = cds.ParserClass().Default()
+	var documents []cds.DocumentLike
+	for _, name := range flags.Args() {
+		var source, err = readSource(name, environment)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v\n", err)
+			return 2
+		}
+		var document cds.DocumentLike
+		document, err = parser.ParseDocumentSafely(source)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v:%v\n", displayName(name), err)
+			return 2
+		}
+		documents = append(documents, document)
+	}
+
+	var changes = cds.DifferClass().Default().DiffDocuments(documents[0], documents[1])
+	var iterator = changes.GetIterator()
+	for iterator.HasNext() {
+		fmt.Fprintf(environment.stdout, "%v\n", iterator.GetNext())
+	}
+	if changes.IsEmpty() {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,110 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package main
+
+import (
+	flg "flag"
+	fmt "fmt"
+	cds "github.com/craterdog/go-cdsn-validation/v3"
+	dif "github.com/pmezard/go-difflib/difflib"
+	osx "os"
+	sts "strings"
+)
+
+// This private function runs the fmt command, which formats each grammar
+// canonically.  By default the formatted grammars are written to the standard
+// output.  The exit status is 1 if a grammar cannot be parsed or, when diffs
+// are displayed without rewriting the files, if a grammar is not formatted
+// canonically.
+func runFormat(arguments []string, environment *environment_) int {
+	var flags = flg.NewFlagSet("fmt", flg.ContinueOnError)
+	flags.SetOutput(environment.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(environment.stderr, "usage: cdsn fmt [-w] [-d] [files]\n")
+		flags.PrintDefaults()
+	}
+	var write = flags.Bool("w", false, "write the result to the source file instead of the standard output")
+	var diff = flags.Bool("d", false, "display the differences instead of the formatted grammar")
+	if flags.Parse(arguments) != nil {
+		return 2
+	}
+	var names, err = expandArguments(flags.Args())
+	if err != nil {
+		fmt.Fprintf(environment.stderr, "cdsn fmt: %v\n", err)
+		return 2
+	}
+
+	var status = 0
+	var parser = cds.ParserClass().Default()
+	var formatter = cds.FormatterClass().Default()
+	for _, name := range names {
+		if name == "-" && *write {
+			fmt.Fprintf(environment.stderr, "cdsn fmt: cannot use -w with the standard input\n")
+			return 2
+		}
+		var source, err = readSource(name, environment)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v\n", err)
+			status = 1
+			continue
+		}
+		var document cds.DocumentLike
+		document, err = parser.ParseDocumentSafely(source)
+		if err != nil {
+			fmt.Fprintf(environment.stderr, "%v:%v\n", displayName(name), err)
+			status = 1
+			continue
+		}
+		var formatted = formatter.FormatDocument(document)
+		if *diff && formatted != source {
+			fmt.Fprint(environment.stdout, formatDiff(displayName(name), source, formatted))
+			if !*write {
+				status = 1
+			}
+		}
+		if *write && formatted != source {
+			var info, _ = osx.Stat(name)
+			err = osx.WriteFile(name, []byte(formatted), info.Mode().Perm())
+			if err != nil {
+				fmt.Fprintf(environment.stderr, "%v\n", err)
+				status = 1
+			}
+		}
+		if !*write && !*diff {
+			fmt.Fprint(environment.stdout, formatted)
+		}
+	}
+	return status
+}
+
+// This private function returns the unified diff between the original and
+// formatted sources for the specified file.
+func formatDiff(name string, original string, formatted string) string {
+	var diff = dif.UnifiedDiff{
+		A:        splitLines(original),
+		B:        splitLines(formatted),
+		FromFile: name + ".orig",
+		ToFile:   name,
+		Context:  3,
+	}
+	var result, _ = dif.GetUnifiedDiffString(diff)
+	return result
+}
+
+// This private function returns the lines of the specified text, each of which
+// retains its end of line character.
+func splitLines(text string) []string {
+	var lines = sts.SplitAfter(text, "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,711 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package main
+
+import (
+	buf "bufio"
+	jsn "encoding/json"
+	flg "flag"
+	fmt "fmt"
+	cds "github.com/craterdog/go-cdsn-validation/v3"
+	io "io"
+	srt "sort"
+	stc "strconv"
+	sts "strings"
+	uni "unicode"
+	u16 "unicode/utf16"
+	ut8 "unicode/utf8"
+)
+
+// This private function runs the lsp command, which serves the Language Server
+// Protocol over the standard input and output.  The exit status is 0 if the
+// client shuts the server down before telling it to exit.
+func runServer(arguments []string, environment *environment_) int {
+	var flags = flg.NewFlagSet("lsp", flg.ContinueOnError)
+	flags.SetOutput(environment.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(environment.stderr, "usage: cdsn lsp\n")
+		flags.PrintDefaults()
+	}
+	if flags.Parse(arguments) != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	var server = &server_{
+		documents: make(map[string]*document_),
+		input:     buf.NewReader(environment.stdin),
+		output:    environment.stdout,
+	}
+	return server.serve(environment.stderr)
+}
+
+// This private type holds the state of the language server.
+type server_ struct {
+	documents  map[string]*document_ // The open documents keyed by URI.
+	input      *buf.Reader
+	isShutdown bool // Whether or not the client has requested a shutdown.
+	output     io.Writer
+}
+
+// This private type holds an open grammar along with its parse tree, which is
+// nil if the grammar cannot be parsed.
+type document_ struct {
+	grammar cds.DocumentLike
+	text    string
+}
+
+// This private type defines the handler for a request or notification.  The
+// result of a notification is ignored.
+type handler_ func(server *server_, params jsn.RawMessage) (any, error)
+
+// This private variable defines the methods that are supported.
+var handlers_ = map[string]handler_{
+	"initialize":              (*server_).initialize,
+	"initialized":             (*server_).ignore,
+	"shutdown":                (*server_).shutdown,
+	"textDocument/completion": (*server_).complete,
+	"textDocument/definition": (*server_).findDefinition,
+	"textDocument/didChange":  (*server_).changeDocument,
+	"textDocument/didClose":   (*server_).closeDocument,
+	"textDocument/didOpen":    (*server_).openDocument,
+	"textDocument/formatting": (*server_).formatDocument,
+	"textDocument/hover":      (*server_).hover,
+	"textDocument/references": (*server_).findReferences,
+}
+
+// This private variable lists the intrinsics that are offered as completions.
+var intrinsics_ = []string{
+	"ANY", "CONTROL", "DIGIT", "EOF", "EOL", "ESCAPE", "LOWER", "UPPER",
+}
+
+// This private method reads and handles each message from the client until it
+// is told to exit or the input ends.
+func (v *server_) serve(log io.Writer) int {
+	for {
+		var body, err = v.readMessage()
+		if err == io.EOF {
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(log, "cdsn lsp: %v\n", err)
+			return 1
+		}
+		var request request_
+		err = jsn.Unmarshal(body, &request)
+		if err != nil {
+			v.writeMessage(errorResponse_{"2.0", nil, responseError_{parseError_, err.Error()}})
+			continue
+		}
+		if request.Method == "exit" {
+			if v.isShutdown {
+				return 0
+			}
+			return 1
+		}
+		v.handleRequest(request)
+	}
+}
+
+// This private method dispatches the specified request to its handler and
+// responds to it unless it is a notification.
+func (v *server_) handleRequest(request request_) {
+	var isNotification = len(request.ID) == 0
+	var handler, ok = handlers_[request.Method]
+	switch {
+	case v.isShutdown && !isNotification:
+		var message = "The server has been shut down."
+		v.writeMessage(errorResponse_{"2.0", request.ID, responseError_{invalidRequest_, message}})
+	case !ok && !isNotification:
+		var message = fmt.Sprintf("The method is not supported: %v", request.Method)
+		v.writeMessage(errorResponse_{"2.0", request.ID, responseError_{methodNotFound_, message}})
+	case !ok:
+		// Unsupported notifications are ignored.
+	default:
+		var result, err = handler(v, request.Params)
+		switch {
+		case isNotification:
+		case err != nil:
+			v.writeMessage(errorResponse_{"2.0", request.ID, responseError_{invalidParams_, err.Error()}})
+		default:
+			v.writeMessage(response_{"2.0", request.ID, result})
+		}
+	}
+}
+
+// This private method reads the body of the next message, which is preceded by
+// a header containing its length.
+func (v *server_) readMessage() ([]byte, error) {
+	var length = -1
+	for {
+		var line, err = v.input.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = sts.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			break
+		}
+		var name, value, _ = sts.Cut(line, ":")
+		if sts.EqualFold(name, "Content-Length") {
+			length, err = stc.Atoi(sts.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid content length: %v", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing content length")
+	}
+	var body = make([]byte, length)
+	var _, err = io.ReadFull(v.input, body)
+	return body, err
+}
+
+// This private method writes the specified message preceded by a header
+// containing its length.
+func (v *server_) writeMessage(message any) {
+	var body, _ = jsn.Marshal(message)
+	fmt.Fprintf(v.output, "Content-Length: %v\r\n\r\n%s", len(body), body)
+}
+
+// This private method parses and validates the specified text, replacing the
+// document with the specified URI, and publishes the problems that are found.
+func (v *server_) updateDocument(uri string, text string) {
+	var document = &document_{
+		text: text,
+	}
+	var diagnostics = []diagnostic_{}
+	var grammar, err = cds.ParserClass().Default().ParseDocumentSafely(text)
+	if err != nil {
+		var problem, ok = err.(cds.ParseErrorLike)
+		var start = position_{}
+		var end = position_{}
+		var message = err.Error()
+		if ok {
+			var offset = offsetOfColumn(text, problem.GetLine(), problem.GetPosition())
+			start = positionOf(text, offset)
+			end = positionOf(text, offset+len(problem.GetTokenValue()))
+			message = problem.GetMessage()
+		}
+		diagnostics = append(diagnostics, diagnostic_{
+			Message:  message,
+			Range:    range_{start, end},
+			Severity: severityError_,
+			Source:   "cdsn",
+		})
+	} else {
+		document.grammar = grammar
+		var iterator = cds.ValidatorClass().Default().DiagnoseDocument(grammar).GetIterator()
+		for iterator.HasNext() {
+			var problem = iterator.GetNext()
+			var span = rangeOf(text, problem.GetSpan())
+			var severity = severityWarning_
+			if problem.GetSeverity() == cds.DiagnosticClass().GetError() {
+				severity = severityError_
+			}
+			var message = problem.GetMessage()
+			if len(problem.GetSymbol()) > 0 {
+				message = problem.GetSymbol() + ": " + message
+			}
+			diagnostics = append(diagnostics, diagnostic_{
+				Message:  message,
+				Range:    span,
+				Severity: severity,
+				Source:   "cdsn",
+			})
+		}
+	}
+	v.documents[uri] = document
+	v.publishDiagnostics(uri, diagnostics)
+}
+
+func (v *server_) publishDiagnostics(uri string, diagnostics []diagnostic_) {
+	var params = publishDiagnosticsParams_{
+		Diagnostics: diagnostics,
+		URI:         uri,
+	}
+	v.writeMessage(notification_{"2.0", "textDocument/publishDiagnostics", params})
+}
+
+// This private method returns the open document and the name that is defined
+// or referenced at the specified position within it.  The name is empty if
+// there is no name at the position.
+func (v *server_) findName(params jsn.RawMessage) (*document_, string, string, error) {
+	var request positionParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, "", "", err
+	}
+	var uri = request.TextDocument.URI
+	var document = v.documents[uri]
+	if document == nil || document.grammar == nil {
+		return document, uri, "", nil
+	}
+	var offset = offsetOf(document.text, request.Position)
+	for _, occurrence := range collectOccurrences(document.grammar) {
+		if offset >= occurrence.start && offset <= occurrence.end {
+			return document, uri, occurrence.name, nil
+		}
+	}
+	return document, uri, "", nil
+}
+
+// Handlers
+
+func (v *server_) changeDocument(params jsn.RawMessage) (any, error) {
+	var request didChangeParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil || len(request.ContentChanges) == 0 {
+		return nil, err
+	}
+	// The full text of the document is synchronized on each change.
+	var changes = request.ContentChanges
+	v.updateDocument(request.TextDocument.URI, changes[len(changes)-1].Text)
+	return nil, nil
+}
+
+func (v *server_) closeDocument(params jsn.RawMessage) (any, error) {
+	var request didCloseParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, err
+	}
+	delete(v.documents, request.TextDocument.URI)
+	v.publishDiagnostics(request.TextDocument.URI, []diagnostic_{})
+	return nil, nil
+}
+
+func (v *server_) complete(params jsn.RawMessage) (any, error) {
+	var request positionParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, err
+	}
+	var items = []completionItem_{}
+	var document = v.documents[request.TextDocument.URI]
+	if document != nil && document.grammar != nil {
+		var iterator = document.grammar.GetGrammar().GetStatements().GetIterator()
+		for iterator.HasNext() {
+			var definition = iterator.GetNext().GetDefinition()
+			if definition == nil {
+				continue
+			}
+			var name = definition.GetSymbol()[1:]
+			var item = completionItem_{
+				Detail: "token",
+				Kind:   completionConstant_,
+				Label:  name,
+			}
+			if isRuleName(name) {
+				item.Detail = "rule"
+				item.Kind = completionFunction_
+			}
+			items = append(items, item)
+		}
+	}
+	for _, intrinsic := range intrinsics_ {
+		var item = completionItem_{
+			Detail: "intrinsic",
+			Kind:   completionKeyword_,
+			Label:  intrinsic,
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (v *server_) findDefinition(params jsn.RawMessage) (any, error) {
+	var document, uri, name, err = v.findName(params)
+	if err != nil || len(name) == 0 {
+		return nil, err
+	}
+	for _, occurrence := range collectOccurrences(document.grammar) {
+		if occurrence.isDefinition && occurrence.name == name {
+			return location_{rangeOfOffsets(document.text, occurrence.start, occurrence.end), uri}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (v *server_) findReferences(params jsn.RawMessage) (any, error) {
+	var request referenceParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, err
+	}
+	var document, uri, name, _ = v.findName(params)
+	if len(name) == 0 {
+		return nil, nil
+	}
+	var locations = []location_{}
+	for _, occurrence := range collectOccurrences(document.grammar) {
+		if occurrence.name != name || occurrence.isDefinition && !request.Context.IncludeDeclaration {
+			continue
+		}
+		var span = rangeOfOffsets(document.text, occurrence.start, occurrence.end)
+		locations = append(locations, location_{span, uri})
+	}
+	return locations, nil
+}
+
+func (v *server_) formatDocument(params jsn.RawMessage) (any, error) {
+	var request formattingParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, err
+	}
+	var document = v.documents[request.TextDocument.URI]
+	if document == nil || document.grammar == nil {
+		return nil, nil
+	}
+	var formatted = cds.FormatterClass().Default().FormatDocument(document.grammar)
+	var edits = []textEdit_{}
+	if formatted != document.text {
+		var span = rangeOfOffsets(document.text, 0, len(document.text))
+		edits = append(edits, textEdit_{formatted, span})
+	}
+	return edits, nil
+}
+
+func (v *server_) hover(params jsn.RawMessage) (any, error) {
+	var document, _, name, err = v.findName(params)
+	if err != nil || len(name) == 0 {
+		return nil, err
+	}
+	var iterator = document.grammar.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil && definition.GetSymbol() == "$"+name {
+			var formatted = cds.FormatterClass().Default().FormatDefinition(definition)
+			var contents = markupContent_{
+				Kind:  "markdown",
+				Value: "```cdsn\n" + formatted + "\n```",
+			}
+			return hover_{contents}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (v *server_) ignore(params jsn.RawMessage) (any, error) {
+	return nil, nil
+}
+
+func (v *server_) initialize(params jsn.RawMessage) (any, error) {
+	var result = initializeResult_{
+		Capabilities: capabilities_{
+			CompletionProvider:         struct{}{},
+			DefinitionProvider:         true,
+			DocumentFormattingProvider: true,
+			HoverProvider:              true,
+			ReferencesProvider:         true,
+			TextDocumentSync:           syncFull_,
+		},
+		ServerInfo: serverInfo_{"cdsn"},
+	}
+	return result, nil
+}
+
+func (v *server_) openDocument(params jsn.RawMessage) (any, error) {
+	var request didOpenParams_
+	var err = jsn.Unmarshal(params, &request)
+	if err != nil {
+		return nil, err
+	}
+	v.updateDocument(request.TextDocument.URI, request.TextDocument.Text)
+	return nil, nil
+}
+
+func (v *server_) shutdown(params jsn.RawMessage) (any, error) {
+	v.isShutdown = true
+	return nil, nil
+}
+
+// This private type records where a name is defined or referenced within a
+// document.  The start and end are byte offsets.
+type occurrence_ struct {
+	end          int
+	isDefinition bool
+	name         string
+	start        int
+}
+
+// This private type is a visitor that collects the occurrences of each name
+// within a document.
+type occurrenceCollector_ struct {
+	cds.VisitorLike
+	occurrences []occurrence_
+}
+
+func (v *occurrenceCollector_) PreDefinition(
+	definition cds.DefinitionLike,
+	walker cds.WalkerLike,
+) bool {
+	var span = definition.GetSpan()
+	if span != nil {
+		var symbol = definition.GetSymbol()
+		var start = span.GetStart().GetOffset()
+		var occurrence = occurrence_{
+			end:          start + len(symbol),
+			isDefinition: true,
+			name:         symbol[1:],
+			start:        start,
+		}
+		v.occurrences = append(v.occurrences, occurrence)
+	}
+	return true
+}
+
+func (v *occurrenceCollector_) PreElement(
+	element cds.ElementLike,
+	walker cds.WalkerLike,
+) bool {
+	var name = element.GetName()
+	var span = element.GetSpan()
+	if len(name) > 0 && span != nil {
+		var occurrence = occurrence_{
+			end:   span.GetEnd().GetOffset(),
+			name:  name,
+			start: span.GetStart().GetOffset(),
+		}
+		v.occurrences = append(v.occurrences, occurrence)
+	}
+	return true
+}
+
+// This private function returns the occurrences of each name within the
+// specified document in the order that they appear.
+func collectOccurrences(document cds.DocumentLike) []occurrence_ {
+	var collector = &occurrenceCollector_{
+		VisitorLike: cds.VisitorClass().Default(),
+	}
+	cds.WalkerClass().FromVisitor(collector).WalkDocument(document)
+	srt.SliceStable(collector.occurrences, func(i, j int) bool {
+		return collector.occurrences[i].start < collector.occurrences[j].start
+	})
+	return collector.occurrences
+}
+
+// This private function determines whether or not the specified name is the
+// name of a rule rather than a token.
+func isRuleName(name string) bool {
+	return len(name) > 0 && uni.IsLower([]rune(name)[0])
+}
+
+// This private function returns the byte offset of the specified protocol
+// position, whose character is counted in UTF-16 code units, within the text.
+func offsetOf(text string, position position_) int {
+	var offset = 0
+	for line := 0; line < position.Line; line++ {
+		var index = sts.IndexByte(text[offset:], '\n')
+		if index < 0 {
+			return len(text)
+		}
+		offset += index + 1
+	}
+	for units := 0; units < position.Character && offset < len(text); {
+		var character, size = ut8.DecodeRuneInString(text[offset:])
+		if character == '\n' {
+			break
+		}
+		units += len(u16.Encode([]rune{character}))
+		offset += size
+	}
+	return offset
+}
+
+// This private function returns the byte offset of the specified line and rune
+// position, both starting at one, within the text.
+func offsetOfColumn(text string, line int, position int) int {
+	var offset = offsetOf(text, position_{0, line - 1})
+	for count := 1; count < position && offset < len(text); count++ {
+		var _, size = ut8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// This private function returns the protocol position of the specified byte
+// offset within the text.
+func positionOf(text string, offset int) position_ {
+	offset = min(offset, len(text))
+	var line = sts.Count(text[:offset], "\n")
+	var start = sts.LastIndexByte(text[:offset], '\n') + 1
+	var character = len(u16.Encode([]rune(text[start:offset])))
+	return position_{character, line}
+}
+
+// This private function returns the protocol range of the specified span
+// within the text, or an empty range at the start if there is no span.
+func rangeOf(text string, span cds.SpanLike) range_ {
+	if span == nil {
+		return range_{}
+	}
+	return rangeOfOffsets(text, span.GetStart().GetOffset(), span.GetEnd().GetOffset())
+}
+
+func rangeOfOffsets(text string, start int, end int) range_ {
+	return range_{positionOf(text, start), positionOf(text, end)}
+}
+
+// PROTOCOL TYPES
+
+// These private types define the subset of the Language Server Protocol that is
+// supported.
+
+type capabilities_ struct {
+	CompletionProvider         struct{} `json:"completionProvider"`
+	DefinitionProvider         bool     `json:"definitionProvider"`
+	DocumentFormattingProvider bool     `json:"documentFormattingProvider"`
+	HoverProvider              bool     `json:"hoverProvider"`
+	ReferencesProvider         bool     `json:"referencesProvider"`
+	TextDocumentSync           int      `json:"textDocumentSync"`
+}
+
+type completionItem_ struct {
+	Detail string `json:"detail"`
+	Kind   int    `json:"kind"`
+	Label  string `json:"label"`
+}
+
+type diagnostic_ struct {
+	Message  string `json:"message"`
+	Range    range_ `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+}
+
+type didChangeParams_ struct {
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	TextDocument textDocument_ `json:"textDocument"`
+}
+
+type didCloseParams_ struct {
+	TextDocument textDocument_ `json:"textDocument"`
+}
+
+type didOpenParams_ struct {
+	TextDocument textDocument_ `json:"textDocument"`
+}
+
+type errorResponse_ struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      jsn.RawMessage `json:"id"`
+	Error   responseError_ `json:"error"`
+}
+
+type formattingParams_ struct {
+	TextDocument textDocument_ `json:"textDocument"`
+}
+
+type hover_ struct {
+	Contents markupContent_ `json:"contents"`
+}
+
+type initializeResult_ struct {
+	Capabilities capabilities_ `json:"capabilities"`
+	ServerInfo   serverInfo_   `json:"serverInfo"`
+}
+
+type location_ struct {
+	Range range_ `json:"range"`
+	URI   string `json:"uri"`
+}
+
+type markupContent_ struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type notification_ struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type position_ struct {
+	Character int `json:"character"`
+	Line      int `json:"line"`
+}
+
+type positionParams_ struct {
+	Position     position_     `json:"position"`
+	TextDocument textDocument_ `json:"textDocument"`
+}
+
+type publishDiagnosticsParams_ struct {
+	Diagnostics []diagnostic_ `json:"diagnostics"`
+	URI         string        `json:"uri"`
+}
+
+type range_ struct {
+	Start position_ `json:"start"`
+	End   position_ `json:"end"`
+}
+
+type referenceParams_ struct {
+	Context struct {
+		IncludeDeclaration bool `json:"includeDeclaration"`
+	} `json:"context"`
+}
+
+type request_ struct {
+	ID     jsn.RawMessage `json:"id"`
+	Method string         `json:"method"`
+	Params jsn.RawMessage `json:"params"`
+}
+
+type response_ struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      jsn.RawMessage `json:"id"`
+	Result  any            `json:"result"`
+}
+
+type responseError_ struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type serverInfo_ struct {
+	Name string `json:"name"`
+}
+
+type textDocument_ struct {
+	Text string `json:"text,omitempty"`
+	URI  string `json:"uri"`
+}
+
+type textEdit_ struct {
+	NewText string `json:"newText"`
+	Range   range_ `json:"range"`
+}
+
+// These private constants define the protocol codes that are used.
+const (
+	completionConstant_ = 21
+	completionFunction_ = 3
+	completionKeyword_  = 14
+	invalidParams_      = -32602
+	invalidRequest_     = -32600
+	methodNotFound_     = -32601
+	parseError_         = -32700
+	severityError_      = 1
+	severityWarning_    = 2
+	syncFull_           = 1
+)
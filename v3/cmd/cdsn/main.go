@@ -0,0 +1,175 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+/*
+The cdsn command provides tools for working with grammars defined using the
+Crater Dog Syntax Notation™ (CDSN).
+
+Usage:
+
+	cdsn <command> [arguments]
+
+The commands are:
+
+	check    parse and validate grammars
+	diff     list the structural changes between two grammars
+	fmt      format grammars canonically
+	lsp      run a language server over the standard streams
+
+Each command other than diff and lsp accepts a list of files, glob patterns and
+directories.  The directories are searched recursively for files ending in
+".cdsn".  When no files are specified, or a file is named "-", the grammar is
+read from the standard input.
+
+The diff command compares an old and a new version of a grammar and lists
+each definition that was added, removed or modified, along with the changes to
+the alternatives and factors of each modified definition.  Differences in
+comments, notes and formatting are ignored.
+
+The lsp command serves the Language Server Protocol over the standard input and
+output so that editors can display the problems found in a grammar, navigate
+between the names and their definitions, and format the grammar.
+*/
+package main
+
+import (
+	fmt "fmt"
+	io "io"
+	osx "os"
+	pth "path/filepath"
+	srt "sort"
+	sts "strings"
+)
+
+// This private type defines a command along with a summary of what it does.
+type command_ struct {
+	run     func(arguments []string, environment *environment_) int
+	summary string
+}
+
+// This private type holds the standard streams used by the commands so that
+// they can be redirected when testing.
+type environment_ struct {
+	stderr io.Writer
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// This private variable defines the commands that are supported.
+var commands_ = map[string]command_{
+	"check": {runCheck, "parse and validate grammars"},
+	"diff":  {runDiff, "list the structural changes between two grammars"},
+	"fmt":   {runFormat, "format grammars canonically"},
+	"lsp":   {runServer, "run a language server over the standard streams"},
+}
+
+func main() {
+	var environment = &environment_{
+		stderr: osx.Stderr,
+		stdin:  osx.Stdin,
+		stdout: osx.Stdout,
+	}
+	osx.Exit(run(osx.Args[1:], environment))
+}
+
+// This private function runs the command named by the first argument and
+// returns its exit status.  An exit status of 2 indicates a usage error.
+func run(arguments []string, environment *environment_) int {
+	if len(arguments) == 0 {
+		printUsage(environment.stderr)
+		return 2
+	}
+	var command, ok = commands_[arguments[0]]
+	if !ok {
+		fmt.Fprintf(environment.stderr, "cdsn: unknown command %q\n", arguments[0])
+		printUsage(environment.stderr)
+		return 2
+	}
+	return command.run(arguments[1:], environment)
+}
+
+// This private function returns the names of the files specified by the
+// arguments.  Glob patterns are expanded and directories are searched for
+// files ending in ".cdsn".  The name "-" denotes the standard input.
+func expandArguments(arguments []string) ([]string, error) {
+	if len(arguments) == 0 {
+		return []string{"-"}, nil
+	}
+	var names []string
+	for _, argument := range arguments {
+		var matches = []string{argument}
+		if sts.ContainsAny(argument, "*?[") {
+			var err error
+			matches, err = pth.Glob(argument)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", argument, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%v: no matching files", argument)
+			}
+		}
+		for _, match := range matches {
+			var info, err = osx.Stat(match)
+			if match == "-" || err != nil || !info.IsDir() {
+				// Missing files are reported when they are read.
+				names = append(names, match)
+				continue
+			}
+			var found []string
+			err = pth.WalkDir(match, func(path string, entry osx.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() && sts.HasSuffix(path, ".cdsn") {
+					found = append(found, path)
+				}
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			srt.Strings(found)
+			names = append(names, found...)
+		}
+	}
+	return names, nil
+}
+
+// This private function prints the usage for the cdsn command.
+func printUsage(output io.Writer) {
+	fmt.Fprintf(output, "usage: cdsn <command> [arguments]\n\nThe commands are:\n\n")
+	var names []string
+	for name := range commands_ {
+		names = append(names, name)
+	}
+	srt.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(output, "    %-8v %v\n", name, commands_[name].summary)
+	}
+}
+
+// This private function returns the source of the specified file, or of the
+// standard input if the name is "-".
+func readSource(name string, environment *environment_) (string, error) {
+	var bytes []byte
+	var err error
+	if name == "-" {
+		bytes, err = io.ReadAll(environment.stdin)
+	} else {
+		bytes, err = osx.ReadFile(name)
+	}
+	return string(bytes), err
+}
+
+// This private function returns the name used for the specified file in
+// messages.
+func displayName(name string) string {
+	if name == "-" {
+		return "<standard input>"
+	}
+	return name
+}
@@ -0,0 +1,192 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package main
+
+import (
+	byt "bytes"
+	jsn "encoding/json"
+	fmt "fmt"
+	ass "github.com/stretchr/testify/assert"
+	osx "os"
+	pth "path/filepath"
+	sts "strings"
+	tes "testing"
+)
+
+// This function runs the cdsn command with the specified arguments and input
+// and returns its exit status and output.
+func runCommand(input string, arguments ...string) (int, string, string) {
+	var stdout, stderr byt.Buffer
+	var environment = &environment_{
+		stderr: &stderr,
+		stdin:  sts.NewReader(input),
+		stdout: &stdout,
+	}
+	var status = run(arguments, environment)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestUsage(t *tes.T) {
+	var status, _, stderr = runCommand("")
+	ass.Equal(t, 2, status)
+	ass.True(t, sts.HasPrefix(stderr, "usage: cdsn <command> [arguments]\n"))
+	status, _, stderr = runCommand("", "bogus")
+	ass.Equal(t, 2, status)
+	ass.True(t, sts.HasPrefix(stderr, "cdsn: unknown command \"bogus\"\n"))
+}
+
+func TestFormat(t *tes.T) {
+	var status, stdout, _ = runCommand("$x:  \"a\"|  \"b\"\n", "fmt")
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "$x: \"a\" | \"b\"\n", stdout)
+
+	status, stdout, _ = runCommand("$x:  \"a\"\n", "fmt", "-d", "-")
+	ass.Equal(t, 1, status)
+	ass.Equal(t, `--- <standard input>.orig
++++ <standard input>
+@@ -1 +1 @@
+-$x:  "a"
++$x: "a"
+`, stdout)
+
+	var directory = t.TempDir()
+	var file = pth.Join(directory, "test.cdsn")
+	osx.WriteFile(file, []byte("$x:  \"a\"\n"), 0644)
+	status, stdout, _ = runCommand("", "fmt", "-w", directory)
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "", stdout)
+	var bytes, _ = osx.ReadFile(file)
+	ass.Equal(t, "$x: \"a\"\n", string(bytes))
+	status, _, _ = runCommand("", "fmt", "-d", pth.Join(directory, "*.cdsn"))
+	ass.Equal(t, 0, status)
+
+	var stderr string
+	status, _, stderr = runCommand("$x: ~~\"a\"\n", "fmt")
+	ass.Equal(t, 1, status)
+	ass.True(t, sts.HasPrefix(stderr, "<standard input>:1:6: "))
+	status, _, stderr = runCommand("", "fmt", "-w", "-")
+	ass.Equal(t, 2, status)
+	ass.Equal(t, "cdsn fmt: cannot use -w with the standard input\n", stderr)
+}
+
+func TestCheck(t *tes.T) {
+	var status, _, stderr = runCommand("$BAD: rule\n$rule: \"a\"\n", "check")
+	ass.Equal(t, 1, status)
+	ass.Equal(t, "<standard input>:1:7: Error: $BAD: A token definition cannot contain a rule name.\n", stderr)
+	status, _, stderr = runCommand("$document: \"a\"\n$unused: \"b\"\n", "check")
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "<standard input>:2:1: Warning: $unused: The definition for $unused is never used.\n", stderr)
+
+	status, _, stderr = runCommand("$document: \"a\" | \"a\" \"b\"\n", "check")
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "", stderr)
+	status, _, stderr = runCommand("$document: \"a\" | \"a\" \"b\"\n", "check", "-strict")
+	ass.Equal(t, 1, status)
+	ass.True(t, sts.HasPrefix(stderr, "<standard input>:1:12: Error: $document: "))
+
+	status, _, stderr = runCommand("", "check", "missing/*.cdsn")
+	ass.Equal(t, 2, status)
+	ass.Equal(t, "cdsn check: missing/*.cdsn: no matching files\n", stderr)
+}
+
+func TestDiff(t *tes.T) {
+	var directory = t.TempDir()
+	var oldFile = pth.Join(directory, "old.cdsn")
+	var newFile = pth.Join(directory, "new.cdsn")
+	osx.WriteFile(oldFile, []byte("$x: \"a\" b?\n$b: \"b\"\n$c: \"c\"\n"), 0644)
+	osx.WriteFile(newFile, []byte("$x:  \"a\" b*  ! Many.\n$b: \"b\" | \"B\"\n$d: \"d\"\n"), 0644)
+	var status, stdout, _ = runCommand("", "diff", oldFile, newFile)
+	ass.Equal(t, 1, status)
+	ass.Equal(t, `Modified: $x
+    alternative 1, factor 2: widened cardinality of b from ? to *
+Modified: $b
+    added alternative 2: "B"
+Removed: $c
+Added: $d
+`, stdout)
+
+	status, stdout, _ = runCommand("$x:  \"a\"  b?\n$b: \"b\"\n$c: \"c\"\n", "diff", oldFile, "-")
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "", stdout)
+
+	var stderr string
+	status, _, stderr = runCommand("", "diff", oldFile)
+	ass.Equal(t, 2, status)
+	ass.Equal(t, "usage: cdsn diff old new\n", stderr)
+	status, _, stderr = runCommand("$x: ~~\"a\"\n", "diff", "-", newFile)
+	ass.Equal(t, 2, status)
+	ass.True(t, sts.HasPrefix(stderr, "<standard input>:1:6: "))
+}
+
+// This function frames each of the specified messages with a header
+// containing its length.
+func frameMessages(messages ...string) string {
+	var input string
+	for _, message := range messages {
+		input += fmt.Sprintf("Content-Length: %v\r\n\r\n%v", len(message), message)
+	}
+	return input
+}
+
+// This function returns the JSON for each of the messages framed in the
+// specified output.
+func unframeMessages(output string) []string {
+	var messages []string
+	for len(output) > 0 {
+		var header, rest, _ = sts.Cut(output, "\r\n\r\n")
+		var length int
+		fmt.Sscanf(header, "Content-Length: %d", &length)
+		messages = append(messages, rest[:length])
+		output = rest[length:]
+	}
+	return messages
+}
+
+func TestServer(t *tes.T) {
+	var uri = "file:///test.cdsn"
+	var text = "$document: list EOF\n$list:  \"[\" ITEM* \"]\"\n$ITEM: LOWER+\n"
+	var source, _ = jsn.Marshal(text)
+	var input = frameMessages(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"`+uri+`","text":`+string(source)+`}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/definition","params":{"textDocument":{"uri":"`+uri+`"},"position":{"line":1,"character":15}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/references","params":{"textDocument":{"uri":"`+uri+`"},"position":{"line":2,"character":2},"context":{"includeDeclaration":true}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/hover","params":{"textDocument":{"uri":"`+uri+`"},"position":{"line":0,"character":12}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"textDocument/formatting","params":{"textDocument":{"uri":"`+uri+`"},"options":{}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"textDocument/completion","params":{"textDocument":{"uri":"`+uri+`"},"position":{"line":0,"character":0}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"`+uri+`"},"contentChanges":[{"text":"$document: missing\n"}]}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"`+uri+`"},"contentChanges":[{"text":"$document: ~~\"a\"\n"}]}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"bogus","params":{}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	var status, stdout, stderr = runCommand(input, "lsp")
+	ass.Equal(t, 0, status)
+	ass.Equal(t, "", stderr)
+	var messages = unframeMessages(stdout)
+	ass.Equal(t, 11, len(messages))
+	ass.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"completionProvider":{},"definitionProvider":true,"documentFormattingProvider":true,"hoverProvider":true,"referencesProvider":true,"textDocumentSync":1},"serverInfo":{"name":"cdsn"}}}`, messages[0])
+	ass.Equal(t, `{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"diagnostics":[],"uri":"file:///test.cdsn"}}`, messages[1])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":2,"result":{"range":{"start":{"character":0,"line":2},"end":{"character":5,"line":2}},"uri":"file:///test.cdsn"}}`, messages[2])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":3,"result":[{"range":{"start":{"character":12,"line":1},"end":{"character":16,"line":1}},"uri":"file:///test.cdsn"},{"range":{"start":{"character":0,"line":2},"end":{"character":5,"line":2}},"uri":"file:///test.cdsn"}]}`, messages[3])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":4,"result":{"contents":{"kind":"markdown","value":"`+"```"+`cdsn\n$list: \"[\" ITEM* \"]\"\n`+"```"+`"}}}`, messages[4])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":5,"result":[{"newText":"$document: list EOF\n$list: \"[\" ITEM* \"]\"\n$ITEM: LOWER+\n","range":{"start":{"character":0,"line":0},"end":{"character":0,"line":3}}}]}`, messages[5])
+	ass.True(t, sts.HasPrefix(messages[6], `{"jsonrpc":"2.0","id":6,"result":[{"detail":"rule","kind":3,"label":"document"},{"detail":"rule","kind":3,"label":"list"},{"detail":"token","kind":21,"label":"ITEM"},{"detail":"intrinsic","kind":14,"label":"ANY"},`))
+	ass.Equal(t, `{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"diagnostics":[{"message":"The grammar is missing a definition for name: missing","range":{"start":{"character":11,"line":0},"end":{"character":18,"line":0}},"severity":1,"source":"cdsn"}],"uri":"file:///test.cdsn"}}`, messages[7])
+	ass.Equal(t, `{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"diagnostics":[{"message":"An unexpected Delimiter token \"~\" was received, was expecting 'assertion' from: $predicate, $assertion","range":{"start":{"character":12,"line":0},"end":{"character":13,"line":0}},"severity":1,"source":"cdsn"}],"uri":"file:///test.cdsn"}}`, messages[8])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":7,"error":{"code":-32601,"message":"The method is not supported: bogus"}}`, messages[9])
+	ass.Equal(t, `{"jsonrpc":"2.0","id":8,"result":null}`, messages[10])
+
+	// Exiting without a shutdown is an error.
+	status, _, _ = runCommand(frameMessages(`{"jsonrpc":"2.0","method":"exit"}`), "lsp")
+	ass.Equal(t, 1, status)
+}
@@ -0,0 +1,391 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	reg "regexp"
+	stc "strconv"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type compilerClass_ struct {
+	classes      map[string]string
+	maximumCount int
+	patterns     map[string]string
+}
+
+// Private Class Namespace Reference
+
+var compilerClass = &compilerClass_{
+	// The character class for each intrinsic that matches a single character.
+	classes: map[string]string{
+		"CONTROL": `\p{Cc}`,
+		"DIGIT":   `\p{Nd}`,
+		"EOL":     `\n`,
+		"LOWER":   `\p{Ll}`,
+		"UPPER":   `\p{Lu}`,
+	},
+
+	// The largest repetition count supported by Go regular expressions.
+	maximumCount: 1000,
+
+	// The pattern for each intrinsic that is not a character class.
+	patterns: map[string]string{
+		"ANY":    `(?s:.)`,
+		"ESCAPE": `\\(?:[abfnrtv'"\\]|x[0-9a-f]{2}|u[0-9a-f]{4}|U[0-9a-f]{8})`,
+	},
+}
+
+// Public Class Namespace Access
+
+func CompilerClass() CompilerClassLike {
+	return compilerClass
+}
+
+// Public Class Constructors
+
+func (c *compilerClass_) FromDocument(document DocumentLike) CompilerLike {
+	return c.fromDocument(document)
+}
+
+// Private Class Constructors
+
+// This private class constructor creates a new compiler that translates the
+// token definitions in the specified document into Go regular expressions.
+func (c *compilerClass_) fromDocument(document DocumentLike) *compiler_ {
+	var compiler = &compiler_{
+		definitions: make(map[string]DefinitionLike),
+		visiting:    make(map[string]bool),
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			compiler.definitions[definition.GetSymbol()[1:]] = definition
+		}
+	}
+	return compiler
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type compiler_ struct {
+	definitions map[string]DefinitionLike // The definitions keyed by name.
+	symbol      string                    // The symbol being compiled.
+	visiting    map[string]bool           // The token names being compiled.
+}
+
+// Public Interface
+
+func (v *compiler_) CompileDefinition(definition DefinitionLike) (
+	pattern string,
+	err error,
+) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			pattern = ""
+			err = fmt.Errorf("The token definition for %v cannot be compiled: %v",
+				v.symbol, message)
+		}
+	}()
+	v.symbol = definition.GetSymbol()
+	v.visiting = make(map[string]bool)
+	var name = v.symbol[1:]
+	if !isTokenName(name) {
+		panic("Only token definitions can be compiled.")
+	}
+	v.visiting[name] = true
+	pattern = v.compileExpression(definition.GetExpression())
+	_, err = reg.Compile(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+	return pattern, nil
+}
+
+// Private Interface
+
+// This private class method returns the Go regular expression that matches the
+// same text as the specified assertion when it appears within a rule.
+func (v *compiler_) compileTerminal(assertion AssertionLike) (
+	pattern string,
+	err error,
+) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			pattern = ""
+			err = fmt.Errorf("The terminal %v cannot be compiled: %v",
+				describeAssertion(assertion), message)
+		}
+	}()
+	v.symbol = describeAssertion(assertion)
+	v.visiting = make(map[string]bool)
+	pattern = v.compileAssertion(assertion)
+	return pattern, nil
+}
+
+func (v *compiler_) compileAlternative(alternative AlternativeLike) string {
+	var pattern string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		var factor = iterator.GetNext()
+		pattern += v.compileFactor(factor)
+	}
+	return pattern
+}
+
+func (v *compiler_) compileAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.compileElement(element)
+	case glyph != nil && len(glyph.GetLast()) == 0:
+		return quoteCharacter(unquoteCharacter(glyph.GetFirst()))
+	case glyph != nil:
+		return "[" + v.compileGlyph(glyph) + "]"
+	case precedence != nil:
+		return "(?:" + v.compileExpression(precedence.GetExpression()) + ")"
+	default:
+		panic("An assertion must contain an element, glyph or precedence.")
+	}
+}
+
+// This private class method returns the body of a character class that matches
+// the same characters as the specified assertion.  The assertion must match a
+// single character for it to be inverted.
+func (v *compiler_) compileCharacters(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case glyph != nil:
+		return v.compileGlyph(glyph)
+	case precedence != nil:
+		var characters string
+		var alternatives = precedence.GetExpression().GetAlternatives().GetIterator()
+		for alternatives.HasNext() {
+			var factors = alternatives.GetNext().GetFactors()
+			var factor = factors.AsArray()[0]
+			if factors.GetSize() != 1 || factor.GetCardinality() != nil ||
+				factor.GetPredicate().IsInverted() {
+				panic("Only single characters can be inverted.")
+			}
+			characters += v.compileCharacters(factor.GetPredicate().GetAssertion())
+		}
+		return characters
+	}
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case len(compilerClass.classes[intrinsic]) > 0:
+		return compilerClass.classes[intrinsic]
+	case len(intrinsic) > 0:
+		panic(fmt.Sprintf("The intrinsic %v cannot be inverted.", intrinsic))
+	case len(literal) > 0:
+		var runes = []rune(unquoteLiteral(literal))
+		if len(runes) != 1 {
+			panic(fmt.Sprintf("The literal %v cannot be inverted.", literal))
+		}
+		return quoteCharacter(runes[0])
+	default:
+		v.enterName(name)
+		var definition = v.definitions[name]
+		var precedence = PrecedenceClass().FromExpression(definition.GetExpression())
+		var characters = v.compileCharacters(AssertionClass().FromPrecedence(precedence))
+		v.exitName(name)
+		return characters
+	}
+}
+
+func (v *compiler_) compileElement(element ElementLike) string {
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case len(compilerClass.classes[intrinsic]) > 0:
+		return compilerClass.classes[intrinsic]
+	case len(compilerClass.patterns[intrinsic]) > 0:
+		return compilerClass.patterns[intrinsic]
+	case len(intrinsic) > 0:
+		panic(fmt.Sprintf("The intrinsic %v cannot be matched within a token.", intrinsic))
+	case len(literal) > 0:
+		return reg.QuoteMeta(unquoteLiteral(literal))
+	default:
+		v.enterName(name)
+		var definition = v.definitions[name]
+		var pattern = "(?:" + v.compileExpression(definition.GetExpression()) + ")"
+		v.exitName(name)
+		return pattern
+	}
+}
+
+func (v *compiler_) compileExpression(expression ExpressionLike) string {
+	var patterns []string
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		patterns = append(patterns, v.compileAlternative(alternative))
+	}
+	return sts.Join(patterns, "|")
+}
+
+func (v *compiler_) compileFactor(factor FactorLike) string {
+	var predicate = factor.GetPredicate()
+	var assertion = predicate.GetAssertion()
+	var pattern string
+	if predicate.IsInverted() {
+		pattern = "[^" + v.compileCharacters(assertion) + "]"
+	} else {
+		pattern = v.compileAssertion(assertion)
+	}
+	var cardinality = factor.GetCardinality()
+	if cardinality == nil {
+		return pattern
+	}
+	if len([]rune(pattern)) > 1 && !isAtomic(pattern) {
+		pattern = "(?:" + pattern + ")"
+	}
+	var minimum, maximum = getLimits(cardinality)
+	if minimum > compilerClass.maximumCount || maximum > compilerClass.maximumCount {
+		panic(fmt.Sprintf("A repetition count cannot be larger than %v.",
+			compilerClass.maximumCount))
+	}
+	var quantifier string
+	switch {
+	case minimum == 0 && maximum == 1:
+		quantifier = "?"
+	case minimum == 0 && maximum < 0:
+		quantifier = "*"
+	case minimum == 1 && maximum < 0:
+		quantifier = "+"
+	case minimum == maximum:
+		quantifier = "{" + stc.Itoa(minimum) + "}"
+	case maximum < 0:
+		quantifier = "{" + stc.Itoa(minimum) + ",}"
+	default:
+		quantifier = "{" + stc.Itoa(minimum) + "," + stc.Itoa(maximum) + "}"
+	}
+	var element = assertion.GetElement()
+	if !predicate.IsInverted() && element != nil && element.GetIntrinsic() == "ANY" &&
+		minimum != maximum {
+		// Repetitions of any character are not greedy.
+		quantifier += "?"
+	}
+	return pattern + quantifier
+}
+
+func (v *compiler_) compileGlyph(glyph GlyphLike) string {
+	var characters = quoteCharacter(unquoteCharacter(glyph.GetFirst()))
+	var last = glyph.GetLast()
+	if len(last) > 0 {
+		characters += "-" + quoteCharacter(unquoteCharacter(last))
+	}
+	return characters
+}
+
+func (v *compiler_) enterName(name string) {
+	var _, ok = v.definitions[name]
+	if !ok {
+		panic(fmt.Sprintf("The name %v is not defined.", name))
+	}
+	if !isTokenName(name) {
+		panic(fmt.Sprintf("The rule %v cannot be referenced by a token.", name))
+	}
+	if v.visiting[name] {
+		panic(fmt.Sprintf("The token %v is recursive.", name))
+	}
+	v.visiting[name] = true
+}
+
+func (v *compiler_) exitName(name string) {
+	delete(v.visiting, name)
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function determines whether or not the specified pattern can be
+// followed by a quantifier without first being grouped.
+func isAtomic(pattern string) bool {
+	switch {
+	case len(pattern) == 2 && pattern[0] == '\\':
+		return true
+	case sts.HasPrefix(pattern, `\p{`) && sts.Index(pattern, "}") == len(pattern)-1:
+		return true
+	case sts.HasPrefix(pattern, "(?") && sts.HasSuffix(pattern, ")"):
+		return isBalanced(pattern[1 : len(pattern)-1])
+	case sts.HasPrefix(pattern, "[") && sts.HasSuffix(pattern, "]"):
+		return sts.Count(pattern, "[") == 1
+	default:
+		return false
+	}
+}
+
+// This private function determines whether or not the parentheses within the
+// specified pattern are balanced, ignoring any that are escaped.
+func isBalanced(pattern string) bool {
+	var depth = 0
+	var escaped = false
+	for _, character := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case character == '\\':
+			escaped = true
+		case character == '(':
+			depth++
+		case character == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// This private function determines whether or not the specified name is the
+// name of a token rather than a rule.
+func isTokenName(name string) bool {
+	return len(name) > 0 && !isRuleName(name)
+}
+
+// This private function returns the specified character quoted so that it can
+// be used within a Go regular expression character class.
+func quoteCharacter(character rune) string {
+	switch {
+	case character == '\n':
+		return `\n`
+	case character < ' ' || character == 0x7f:
+		return fmt.Sprintf(`\x{%x}`, character)
+	case sts.ContainsRune(`\^-[]`, character):
+		return `\` + string(character)
+	default:
+		return reg.QuoteMeta(string(character))
+	}
+}
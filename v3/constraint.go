@@ -57,6 +57,7 @@ func (c *constraintClass_) FromRange(first, last string) ConstraintLike {
 type constraint_ struct {
 	first string
 	last  string
+	span  SpanLike
 }
 
 // Public Interface
@@ -69,6 +70,10 @@ func (v *constraint_) GetLast() string {
 	return v.last
 }
 
+func (v *constraint_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *constraint_) SetFirst(first string) {
 	if len(first) < 1 {
 		panic("A constraint requires a first number.")
@@ -79,3 +84,7 @@ func (v *constraint_) SetFirst(first string) {
 func (v *constraint_) SetLast(last string) {
 	v.last = last
 }
+
+func (v *constraint_) SetSpan(span SpanLike) {
+	v.span = span
+}
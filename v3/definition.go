@@ -54,6 +54,7 @@ func (c *definitionClass_) FromSymbolAndExpression(
 
 type definition_ struct {
 	expression ExpressionLike
+	span       SpanLike
 	symbol     string
 }
 
@@ -63,6 +64,10 @@ func (v *definition_) GetExpression() ExpressionLike {
 	return v.expression
 }
 
+func (v *definition_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *definition_) GetSymbol() string {
 	return v.symbol
 }
@@ -74,6 +79,10 @@ func (v *definition_) SetExpression(expression ExpressionLike) {
 	v.expression = expression
 }
 
+func (v *definition_) SetSpan(span SpanLike) {
+	v.span = span
+}
+
 func (v *definition_) SetSymbol(symbol string) {
 	if len(symbol) < 2 {
 		var message = fmt.Sprintf("An invalid symbol was found:\n    %v\n", symbol)
@@ -0,0 +1,173 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type diagnosticClass_ struct {
+	conflict_      string
+	error_         string
+	invalid_       string
+	leftRecursive_ string
+	lossy_         string
+	recursive_     string
+	undefined_     string
+	unreachable_   string
+	unused_        string
+	warning_       string
+}
+
+// Private Class Namespace Reference
+
+var diagnosticClass = &diagnosticClass_{
+	conflict_:      "Conflict",
+	error_:         "Error",
+	invalid_:       "Invalid",
+	leftRecursive_: "LeftRecursive",
+	lossy_:         "Lossy",
+	recursive_:     "Recursive",
+	undefined_:     "Undefined",
+	unreachable_:   "Unreachable",
+	unused_:        "Unused",
+	warning_:       "Warning",
+}
+
+// Public Class Namespace Access
+
+func DiagnosticClass() DiagnosticClassLike {
+	return diagnosticClass
+}
+
+// Public Class Constants
+
+func (c *diagnosticClass_) GetConflict() string {
+	return c.conflict_
+}
+
+func (c *diagnosticClass_) GetError() string {
+	return c.error_
+}
+
+func (c *diagnosticClass_) GetInvalid() string {
+	return c.invalid_
+}
+
+func (c *diagnosticClass_) GetLeftRecursive() string {
+	return c.leftRecursive_
+}
+
+func (c *diagnosticClass_) GetLossy() string {
+	return c.lossy_
+}
+
+func (c *diagnosticClass_) GetRecursive() string {
+	return c.recursive_
+}
+
+func (c *diagnosticClass_) GetUndefined() string {
+	return c.undefined_
+}
+
+func (c *diagnosticClass_) GetUnreachable() string {
+	return c.unreachable_
+}
+
+func (c *diagnosticClass_) GetUnused() string {
+	return c.unused_
+}
+
+func (c *diagnosticClass_) GetWarning() string {
+	return c.warning_
+}
+
+// Public Class Constructors
+
+func (c *diagnosticClass_) FromKind(
+	kind string,
+	severity string,
+	symbol string,
+	message string,
+	span SpanLike,
+) DiagnosticLike {
+	var diagnostic = &diagnostic_{
+		kind:     kind,
+		message:  message,
+		severity: severity,
+		span:     span,
+		symbol:   symbol,
+	}
+	return diagnostic
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type diagnostic_ struct {
+	kind     string
+	message  string
+	severity string
+	span     SpanLike // Nil if the location of the problem is unknown.
+	symbol   string
+}
+
+// Public Interface
+
+func (v *diagnostic_) GetKind() string {
+	return v.kind
+}
+
+func (v *diagnostic_) GetLine() int {
+	if v.span == nil {
+		return 0
+	}
+	return v.span.GetStart().GetLine()
+}
+
+func (v *diagnostic_) GetMessage() string {
+	return v.message
+}
+
+func (v *diagnostic_) GetPosition() int {
+	if v.span == nil {
+		return 0
+	}
+	return v.span.GetStart().GetPosition()
+}
+
+func (v *diagnostic_) GetSeverity() string {
+	return v.severity
+}
+
+func (v *diagnostic_) GetSpan() SpanLike {
+	return v.span
+}
+
+func (v *diagnostic_) GetSymbol() string {
+	return v.symbol
+}
+
+func (v *diagnostic_) String() string {
+	var s = fmt.Sprintf("%v: %v", v.severity, v.message)
+	if len(v.symbol) > 0 {
+		s = fmt.Sprintf("%v: %v: %v", v.severity, v.symbol, v.message)
+	}
+	if v.span != nil {
+		s = fmt.Sprintf("%v: %v", v.span.GetStart(), s)
+	}
+	return s
+}
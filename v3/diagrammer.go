@@ -0,0 +1,462 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type diagrammerClass_ struct {
+	defaultLinkFormat string
+}
+
+// Private Class Namespace Reference
+
+var diagrammerClass = &diagrammerClass_{
+	// By default each name links to the diagram of its definition within the
+	// same document.
+	defaultLinkFormat: "#%v",
+}
+
+// Public Class Namespace Access
+
+func DiagrammerClass() DiagrammerClassLike {
+	return diagrammerClass
+}
+
+// Public Class Constructors
+
+func (c *diagrammerClass_) Default() DiagrammerLike {
+	return c.FromLinkFormat(c.defaultLinkFormat)
+}
+
+func (c *diagrammerClass_) FromLinkFormat(format string) DiagrammerLike {
+	if !sts.Contains(format, "%v") {
+		panic(fmt.Sprintf("The link format must contain a %%v placeholder: %v", format))
+	}
+	var diagrammer = &diagrammer_{
+		linkFormat: format,
+	}
+	return diagrammer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type diagrammer_ struct {
+	linkFormat string // The format of the link for each name.
+	result     sts.Builder
+}
+
+// Public Interface
+
+func (v *diagrammer_) DiagramDefinition(definition DefinitionLike) string {
+	v.result.Reset()
+	var width, height = v.diagramDefinition(definition, 0)
+	return v.formatSVG(width, height)
+}
+
+func (v *diagrammer_) DiagramDocument(document DocumentLike) string {
+	v.result.Reset()
+	var width, height int
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			var w, h = v.diagramDefinition(definition, height)
+			width = max(width, w)
+			height += h
+		}
+	}
+	return v.formatSVG(width, height)
+}
+
+func (v *diagrammer_) GetLinkFormat() string {
+	return v.linkFormat
+}
+
+// Private Interface
+
+// This private class method appends the diagram for the specified definition
+// at the specified vertical offset and returns the width and height of the
+// diagram.  The diagram is titled with the symbol of the definition and its
+// track runs from a start marker on the left to an end marker on the right.
+func (v *diagrammer_) diagramDefinition(
+	definition DefinitionLike,
+	offset int,
+) (width, height int) {
+	var symbol = definition.GetSymbol()
+	var track = v.layoutExpression(definition.GetExpression())
+	var x = margin_ + markerWidth_
+	var y = margin_ + titleHeight_ + track.up
+	width = x + track.width + markerWidth_ + margin_
+	height = y + track.down + margin_
+	fmt.Fprintf(&v.result, "<g class=\"definition\" id=\"%v\" transform=\"translate(0 %v)\">\n",
+		escapeXML(symbol[1:]), offset)
+	fmt.Fprintf(&v.result, "<text class=\"symbol\" x=\"%v\" y=\"%v\">%v</text>\n",
+		margin_, margin_+titleHeight_-6, escapeXML(symbol))
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v v%v m%v %v v%v m%v %v h%v\"/>\n",
+		margin_, y-arcRadius_, 2*arcRadius_, arcRadius_, -2*arcRadius_, 2*arcRadius_, 0, -arcRadius_, arcRadius_)
+	v.renderTrack(track, x, y)
+	x += track.width
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v h%v m%v %v v%v m%v %v v%v\"/>\n",
+		x, y, markerWidth_, -arcRadius_, -arcRadius_, 2*arcRadius_, arcRadius_, -2*arcRadius_, 2*arcRadius_)
+	v.result.WriteString("</g>\n")
+	return width, height
+}
+
+// This private class method returns the complete SVG document containing the
+// diagrams that have been appended to the result.
+func (v *diagrammer_) formatSVG(width, height int) string {
+	var svg = fmt.Sprintf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\">\n",
+		width, height, width, height,
+	)
+	return svg + diagramStyle_ + v.result.String() + "</svg>\n"
+}
+
+func (v *diagrammer_) layoutAlternative(alternative AlternativeLike) *track_ {
+	var tracks []*track_
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		tracks = append(tracks, v.layoutFactor(iterator.GetNext()))
+	}
+	return makeSequence(tracks)
+}
+
+func (v *diagrammer_) layoutAssertion(assertion AssertionLike) *track_ {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil && len(element.GetName()) > 0:
+		var name = element.GetName()
+		var track = makeBox("nonterminal", name)
+		track.link = fmt.Sprintf(v.linkFormat, name)
+		return track
+	case element != nil || glyph != nil:
+		return makeBox("terminal", describeAssertion(assertion))
+	case precedence != nil:
+		return v.layoutExpression(precedence.GetExpression())
+	default:
+		panic("Attempted to diagram an empty assertion.")
+	}
+}
+
+func (v *diagrammer_) layoutExpression(expression ExpressionLike) *track_ {
+	var tracks []*track_
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		tracks = append(tracks, v.layoutAlternative(iterator.GetNext()))
+	}
+	if len(tracks) == 1 {
+		return tracks[0]
+	}
+	return makeChoice(tracks)
+}
+
+// This private class method returns the track for the specified factor.  An
+// optional factor may be bypassed and a repeated factor is looped back on
+// itself, with any limits on the number of repetitions labeling the loop.
+func (v *diagrammer_) layoutFactor(factor FactorLike) *track_ {
+	var track = v.layoutPredicate(factor.GetPredicate())
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	switch {
+	case minimum == 1 && maximum == 1:
+		return track
+	case maximum == 0:
+		return makeSkip()
+	case maximum == 1:
+		return makeChoice([]*track_{makeSkip(), track})
+	case maximum < 0 && minimum <= 1:
+		track = makeLoop(track, "")
+	case maximum < 0:
+		track = makeLoop(track, fmt.Sprintf("%v or more", minimum))
+	case minimum == maximum:
+		track = makeLoop(track, fmt.Sprintf("exactly %v", minimum))
+	case minimum == 0:
+		track = makeLoop(track, fmt.Sprintf("at most %v", maximum))
+	default:
+		track = makeLoop(track, fmt.Sprintf("%v to %v", minimum, maximum))
+	}
+	if minimum == 0 {
+		track = makeChoice([]*track_{makeSkip(), track})
+	}
+	return track
+}
+
+func (v *diagrammer_) layoutPredicate(predicate PredicateLike) *track_ {
+	var track = v.layoutAssertion(predicate.GetAssertion())
+	if predicate.IsInverted() {
+		track = makeInversion(track)
+	}
+	return track
+}
+
+func (v *diagrammer_) renderBox(track *track_, x, y int) {
+	var radius = 0
+	if track.kind == "terminal" {
+		radius = arcRadius_
+	}
+	if len(track.link) > 0 {
+		fmt.Fprintf(&v.result, "<a href=\"%v\">\n", escapeXML(track.link))
+	}
+	fmt.Fprintf(&v.result,
+		"<rect class=\"%v\" x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" rx=\"%v\"/>\n",
+		track.kind, x, y-track.up, track.width, track.up+track.down, radius)
+	fmt.Fprintf(&v.result, "<text x=\"%v\" y=\"%v\">%v</text>\n",
+		x+track.width/2, y+4, escapeXML(track.label))
+	if len(track.link) > 0 {
+		v.result.WriteString("</a>\n")
+	}
+}
+
+// This private class method renders a choice with its first alternative on
+// the baseline and each remaining alternative on a branch below the previous
+// one.
+func (v *diagrammer_) renderChoice(track *track_, x, y int) {
+	var inner = track.width - 4*arcRadius_
+	for index, alternative := range track.tracks {
+		var offset = track.offsets[index]
+		var left = x + 2*arcRadius_
+		var right = left + alternative.width
+		if offset == 0 {
+			fmt.Fprintf(&v.result, "<path d=\"M%v %v H%v\"/>\n", x, y, left)
+			fmt.Fprintf(&v.result, "<path d=\"M%v %v H%v\"/>\n", right, y, x+track.width)
+		} else {
+			fmt.Fprintf(&v.result,
+				"<path d=\"M%v %v a%v %v 0 0 1 %v %v v%v a%v %v 0 0 0 %v %v\"/>\n",
+				x, y, arcRadius_, arcRadius_, arcRadius_, arcRadius_, offset-2*arcRadius_, arcRadius_, arcRadius_, arcRadius_, arcRadius_)
+			fmt.Fprintf(&v.result,
+				"<path d=\"M%v %v h%v a%v %v 0 0 0 %v %v v%v a%v %v 0 0 1 %v %v\"/>\n",
+				right, y+offset, inner-alternative.width, arcRadius_, arcRadius_, arcRadius_, -arcRadius_,
+				2*arcRadius_-offset, arcRadius_, arcRadius_, arcRadius_, -arcRadius_)
+		}
+		v.renderTrack(alternative, left, y+offset)
+	}
+}
+
+func (v *diagrammer_) renderInversion(track *track_, x, y int) {
+	var inner = track.tracks[0]
+	fmt.Fprintf(&v.result,
+		"<rect class=\"inversion\" x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\"/>\n",
+		x, y-track.up, track.width, track.up+track.down)
+	fmt.Fprintf(&v.result, "<text class=\"marker\" x=\"%v\" y=\"%v\">~</text>\n",
+		x+4, y-track.up+12)
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v h%v\"/>\n", x, y, spacing_)
+	v.renderTrack(inner, x+spacing_, y)
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v h%v\"/>\n", x+spacing_+inner.width, y, spacing_)
+}
+
+// This private class method renders a loop with the repeated track on the
+// baseline and the path back to its start below it.
+func (v *diagrammer_) renderLoop(track *track_, x, y int) {
+	var inner = track.tracks[0]
+	var width = track.width - 2*arcRadius_
+	var left = x + arcRadius_ + (width-inner.width)/2
+	var depth = track.offsets[0]
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v H%v\"/>\n", x, y, left)
+	v.renderTrack(inner, left, y)
+	fmt.Fprintf(&v.result, "<path d=\"M%v %v H%v\"/>\n", left+inner.width, y, x+track.width)
+	fmt.Fprintf(&v.result,
+		"<path d=\"M%v %v a%v %v 0 0 1 %v %v v%v a%v %v 0 0 1 %v %v h%v a%v %v 0 0 1 %v %v v%v a%v %v 0 0 1 %v %v\"/>\n",
+		x+arcRadius_+width, y, arcRadius_, arcRadius_, arcRadius_, arcRadius_, depth-2*arcRadius_, arcRadius_, arcRadius_, -arcRadius_, arcRadius_,
+		-width, arcRadius_, arcRadius_, -arcRadius_, -arcRadius_, 2*arcRadius_-depth, arcRadius_, arcRadius_, arcRadius_, -arcRadius_)
+	if len(track.label) > 0 {
+		fmt.Fprintf(&v.result, "<text class=\"label\" x=\"%v\" y=\"%v\">%v</text>\n",
+			x+arcRadius_+width/2, y+depth+14, escapeXML(track.label))
+	}
+}
+
+func (v *diagrammer_) renderSequence(track *track_, x, y int) {
+	for index, element := range track.tracks {
+		if index > 0 {
+			fmt.Fprintf(&v.result, "<path d=\"M%v %v h%v\"/>\n", x, y, spacing_)
+			x += spacing_
+		}
+		v.renderTrack(element, x, y)
+		x += element.width
+	}
+}
+
+// This private class method renders the specified track with its entry point
+// at the specified coordinates.
+func (v *diagrammer_) renderTrack(track *track_, x, y int) {
+	switch track.kind {
+	case "choice":
+		v.renderChoice(track, x, y)
+	case "inversion":
+		v.renderInversion(track, x, y)
+	case "loop":
+		v.renderLoop(track, x, y)
+	case "nonterminal", "terminal":
+		v.renderBox(track, x, y)
+	case "sequence":
+		v.renderSequence(track, x, y)
+	}
+}
+
+// PRIVATE TYPES
+
+// This private type defines the layout of a track within a railroad diagram.
+// Each track is entered from the left and exited to the right along its
+// baseline, and extends up and down from its baseline by the specified
+// amounts.
+type track_ struct {
+	down    int
+	kind    string
+	label   string
+	link    string
+	offsets []int // The vertical offset of each branch of the track.
+	tracks  []*track_
+	up      int
+	width   int
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the specified text with the characters that
+// are reserved by XML and HTML replaced by their entities.
+func escapeXML(text string) string {
+	var replacer = sts.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+	)
+	return replacer.Replace(text)
+}
+
+// This private function returns a track containing a box of the specified kind
+// with the specified label.
+func makeBox(kind string, label string) *track_ {
+	var track = &track_{
+		down:  boxHeight_ / 2,
+		kind:  kind,
+		label: label,
+		up:    boxHeight_ / 2,
+		width: len([]rune(label))*characterWidth_ + 2*spacing_,
+	}
+	return track
+}
+
+// This private function returns a track that branches into each of the
+// specified tracks.
+func makeChoice(tracks []*track_) *track_ {
+	var track = &track_{
+		kind:    "choice",
+		offsets: []int{0},
+		tracks:  tracks,
+		up:      tracks[0].up,
+	}
+	var offset int
+	for index, branch := range tracks {
+		track.width = max(track.width, branch.width+4*arcRadius_)
+		if index > 0 {
+			offset = max(offset+tracks[index-1].down+spacing_+branch.up, offset+2*arcRadius_)
+			track.offsets = append(track.offsets, offset)
+		}
+	}
+	track.down = offset + tracks[len(tracks)-1].down
+	return track
+}
+
+// This private function returns a track that surrounds the specified track to
+// show that it is inverted.
+func makeInversion(inner *track_) *track_ {
+	var track = &track_{
+		down:   inner.down + spacing_/2,
+		kind:   "inversion",
+		tracks: []*track_{inner},
+		up:     inner.up + titleHeight_/2 + spacing_/2,
+		width:  inner.width + 2*spacing_,
+	}
+	return track
+}
+
+// This private function returns a track that repeats the specified track,
+// labeling the loop with the specified label.
+func makeLoop(inner *track_, label string) *track_ {
+	var depth = max(inner.down+spacing_, 2*arcRadius_)
+	var track = &track_{
+		down:    depth,
+		kind:    "loop",
+		label:   label,
+		offsets: []int{depth},
+		tracks:  []*track_{inner},
+		up:      inner.up,
+		width:   max(inner.width, len([]rune(label))*characterWidth_) + 2*arcRadius_,
+	}
+	if len(label) > 0 {
+		track.down += titleHeight_
+	}
+	return track
+}
+
+// This private function returns a track containing each of the specified
+// tracks in order.
+func makeSequence(tracks []*track_) *track_ {
+	if len(tracks) == 1 {
+		return tracks[0]
+	}
+	var track = &track_{
+		kind:   "sequence",
+		tracks: tracks,
+	}
+	for index, element := range tracks {
+		if index > 0 {
+			track.width += spacing_
+		}
+		track.width += element.width
+		track.up = max(track.up, element.up)
+		track.down = max(track.down, element.down)
+	}
+	return track
+}
+
+// This private function returns an empty track.
+func makeSkip() *track_ {
+	var track = &track_{
+		kind: "skip",
+	}
+	return track
+}
+
+// These private constants define the dimensions of the parts of a railroad
+// diagram in pixels.
+const (
+	arcRadius_      = 10 // The radius of each curve in a track.
+	boxHeight_      = 22 // The height of each box.
+	characterWidth_ = 8  // The approximate width of each character in a label.
+	spacing_        = 10 // The space between adjacent parts.
+	margin_         = 10 // The space around each diagram.
+	markerWidth_    = 20 // The width of the start and end markers.
+	titleHeight_    = 20 // The height of the title of each diagram.
+)
+
+// This private constant defines the style sheet that is embedded within each
+// SVG document.
+const diagramStyle_ = `<style>
+path { fill: none; stroke: black; stroke-width: 2; }
+rect { fill: #eef; stroke: black; stroke-width: 2; }
+rect.inversion { fill: none; stroke-dasharray: 4 2; stroke-width: 1; }
+text { font: 13px monospace; text-anchor: middle; }
+text.label { font-size: 11px; }
+text.marker, text.symbol { font-weight: bold; text-anchor: start; }
+a text { fill: blue; }
+</style>
+`
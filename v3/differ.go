@@ -0,0 +1,470 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	stc "strconv"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type differClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var differClass = &differClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func DifferClass() DifferClassLike {
+	return differClass
+}
+
+// Public Class Constructors
+
+func (c *differClass_) Default() DifferLike {
+	var differ = &differ_{}
+	return differ
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type differ_ struct {
+	// This class does not define any attributes.
+}
+
+// Public Interface
+
+func (v *differ_) DiffDocuments(
+	oldDocument DocumentLike,
+	newDocument DocumentLike,
+) col.Sequential[ChangeLike] {
+	var changes = col.ListClass[ChangeLike]().Empty()
+	var oldDefinitions = collectDefinitions(oldDocument.GetGrammar())
+	var newDefinitions = collectDefinitions(newDocument.GetGrammar())
+
+	// Removed and modified definitions are listed in their original order.
+	for _, oldDefinition := range orderDefinitions(oldDocument.GetGrammar()) {
+		var symbol = oldDefinition.GetSymbol()
+		var newDefinition = newDefinitions[symbol]
+		if newDefinition == nil {
+			var details = col.ListClass[string]().Empty()
+			var change = ChangeClass().FromKind(ChangeClass().GetRemoved(), symbol, details)
+			changes.AppendValue(change)
+			continue
+		}
+		var details = v.diffExpressions(
+			oldDefinition.GetExpression(),
+			newDefinition.GetExpression(),
+			"",
+		)
+		if len(details) > 0 {
+			var list = col.ListClass[string]().FromArray(details)
+			var change = ChangeClass().FromKind(ChangeClass().GetModified(), symbol, list)
+			changes.AppendValue(change)
+		}
+	}
+
+	// Added definitions are listed in their new order.
+	for _, newDefinition := range orderDefinitions(newDocument.GetGrammar()) {
+		var symbol = newDefinition.GetSymbol()
+		if oldDefinitions[symbol] == nil {
+			var details = col.ListClass[string]().Empty()
+			var change = ChangeClass().FromKind(ChangeClass().GetAdded(), symbol, details)
+			changes.AppendValue(change)
+		}
+	}
+	return changes
+}
+
+// Private Interface
+
+// This private class method returns a description of each structural change
+// between the alternatives of the specified expressions.  Added and modified
+// alternatives are numbered by their new position and removed alternatives by
+// their old position.  An alternative that only changed its position, which
+// changes the order in which the alternatives are tried, is described as moved.
+// Each description begins with the specified path, which locates a nested
+// expression within its definition.
+func (v *differ_) diffExpressions(
+	oldExpression ExpressionLike,
+	newExpression ExpressionLike,
+	path string,
+) []string {
+	var oldAlternatives = oldExpression.GetAlternatives().AsArray()
+	var newAlternatives = newExpression.GetAlternatives().AsArray()
+	var oldKeys = make([]string, len(oldAlternatives))
+	for index, alternative := range oldAlternatives {
+		oldKeys[index] = keyOfAlternative(alternative)
+	}
+	var newKeys = make([]string, len(newAlternatives))
+	for index, alternative := range newAlternatives {
+		newKeys[index] = keyOfAlternative(alternative)
+	}
+	var details []string
+	var matches = alignKeys(oldKeys, newKeys)
+
+	// Pair each unmatched old alternative with an identical unmatched new one.
+	var isMatched = make([]bool, len(newKeys))
+	for _, newIndex := range matches {
+		if newIndex >= 0 {
+			isMatched[newIndex] = true
+		}
+	}
+	var isMoved = make([]bool, len(oldKeys))
+	var moves = make([]int, len(newKeys)) // The old index of each paired alternative.
+	for index := range moves {
+		moves[index] = -1
+	}
+	for oldIndex, newIndex := range matches {
+		if newIndex >= 0 {
+			continue
+		}
+		for index, key := range newKeys {
+			if !isMatched[index] && key == oldKeys[oldIndex] {
+				isMatched[index] = true
+				isMoved[oldIndex] = true
+				moves[index] = oldIndex
+				break
+			}
+		}
+	}
+
+	visitAlignment(matches, len(newKeys), func(oldIndex int, newIndex int) {
+		if oldIndex >= 0 && isMoved[oldIndex] {
+			// The move is described at the new position.
+			oldIndex = -1
+		}
+		if newIndex >= 0 && moves[newIndex] >= 0 {
+			if moves[newIndex] != newIndex {
+				var detail = fmt.Sprintf("moved alternative %v to %v: %v",
+					moves[newIndex]+1, newIndex+1, newKeys[newIndex])
+				details = append(details, path+detail)
+			}
+			newIndex = -1
+		}
+		switch {
+		case oldIndex < 0 && newIndex < 0:
+			return
+		case oldIndex < 0:
+			var detail = fmt.Sprintf("added alternative %v: %v", newIndex+1, newKeys[newIndex])
+			details = append(details, path+detail)
+		case newIndex < 0:
+			var detail = fmt.Sprintf("removed alternative %v: %v", oldIndex+1, oldKeys[oldIndex])
+			details = append(details, path+detail)
+		default:
+			var location = fmt.Sprintf("%valternative %v", path, newIndex+1)
+			var changes = v.diffFactors(
+				oldAlternatives[oldIndex].GetFactors().AsArray(),
+				newAlternatives[newIndex].GetFactors().AsArray(),
+				location,
+			)
+			details = append(details, changes...)
+		}
+	})
+	return details
+}
+
+// This private class method returns a description of each structural change
+// between the specified sequences of factors.  Each description begins with
+// the specified path.
+func (v *differ_) diffFactors(
+	oldFactors []FactorLike,
+	newFactors []FactorLike,
+	path string,
+) []string {
+	var oldKeys = make([]string, len(oldFactors))
+	for index, factor := range oldFactors {
+		oldKeys[index] = keyOfFactor(factor)
+	}
+	var newKeys = make([]string, len(newFactors))
+	for index, factor := range newFactors {
+		newKeys[index] = keyOfFactor(factor)
+	}
+	var details []string
+	var matches = alignKeys(oldKeys, newKeys)
+	visitAlignment(matches, len(newKeys), func(oldIndex int, newIndex int) {
+		switch {
+		case oldIndex < 0:
+			var detail = fmt.Sprintf("%v: added factor %v: %v", path, newIndex+1, newKeys[newIndex])
+			details = append(details, detail)
+		case newIndex < 0:
+			var detail = fmt.Sprintf("%v: removed factor %v: %v", path, oldIndex+1, oldKeys[oldIndex])
+			details = append(details, detail)
+		default:
+			var location = fmt.Sprintf("%v, factor %v", path, newIndex+1)
+			var changes = v.diffFactor(oldFactors[oldIndex], newFactors[newIndex], location)
+			details = append(details, changes...)
+		}
+	})
+	return details
+}
+
+// This private class method returns a description of the structural change
+// between the specified factors.  A change to the expression within a
+// precedence is described by the changes to its alternatives.
+func (v *differ_) diffFactor(
+	oldFactor FactorLike,
+	newFactor FactorLike,
+	path string,
+) []string {
+	var oldPredicate = oldFactor.GetPredicate()
+	var newPredicate = newFactor.GetPredicate()
+	var oldMinimum, oldMaximum = getLimits(oldFactor.GetCardinality())
+	var newMinimum, newMaximum = getLimits(newFactor.GetCardinality())
+	if keyOfPredicate(oldPredicate) == keyOfPredicate(newPredicate) {
+		var detail = fmt.Sprintf(
+			"%v: %v cardinality of %v from %v to %v",
+			path,
+			compareRanges(oldMinimum, oldMaximum, newMinimum, newMaximum),
+			keyOfPredicate(newPredicate),
+			describeLimits(oldMinimum, oldMaximum),
+			describeLimits(newMinimum, newMaximum),
+		)
+		return []string{detail}
+	}
+	var isSimilar = oldPredicate.IsInverted() == newPredicate.IsInverted() &&
+		oldMinimum == newMinimum && oldMaximum == newMaximum
+	var oldAssertion = oldPredicate.GetAssertion()
+	var newAssertion = newPredicate.GetAssertion()
+	var oldGlyph = oldAssertion.GetGlyph()
+	var newGlyph = newAssertion.GetGlyph()
+	if isSimilar && oldGlyph != nil && newGlyph != nil {
+		var oldFirst, oldLast = getRunes(oldGlyph)
+		var newFirst, newLast = getRunes(newGlyph)
+		var detail = fmt.Sprintf(
+			"%v: %v glyph range from %v to %v",
+			path,
+			compareRanges(oldFirst, oldLast, newFirst, newLast),
+			keyOfAssertion(oldAssertion),
+			keyOfAssertion(newAssertion),
+		)
+		return []string{detail}
+	}
+	var oldPrecedence = oldAssertion.GetPrecedence()
+	var newPrecedence = newAssertion.GetPrecedence()
+	if isSimilar && oldPrecedence != nil && newPrecedence != nil {
+		return v.diffExpressions(
+			oldPrecedence.GetExpression(),
+			newPrecedence.GetExpression(),
+			path+", ",
+		)
+	}
+	var detail = fmt.Sprintf(
+		"%v: changed %v to %v",
+		path,
+		keyOfFactor(oldFactor),
+		keyOfFactor(newFactor),
+	)
+	return []string{detail}
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns for each of the specified old keys the index
+// of the new key that it is matched with in a longest common subsequence of the
+// two sequences of keys, or -1 if it is not matched.
+func alignKeys(oldKeys []string, newKeys []string) []int {
+	var lengths = make([][]int, len(oldKeys)+1)
+	for index := range lengths {
+		lengths[index] = make([]int, len(newKeys)+1)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		for j := len(newKeys) - 1; j >= 0; j-- {
+			if oldKeys[i] == newKeys[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var matches = make([]int, len(oldKeys))
+	var i, j = 0, 0
+	for i < len(oldKeys) {
+		switch {
+		case j < len(newKeys) && oldKeys[i] == newKeys[j]:
+			matches[i] = j
+			i++
+			j++
+		case j < len(newKeys) && lengths[i][j+1] >= lengths[i+1][j]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+	return matches
+}
+
+// This private function returns whether the second range of values widens,
+// narrows or otherwise changes the first range.  A maximum of -1 means
+// unlimited.
+func compareRanges(oldMinimum, oldMaximum, newMinimum, newMaximum int) string {
+	var contains = func(minimum, maximum, first, last int) bool {
+		return minimum <= first && (maximum < 0 || last >= 0 && last <= maximum)
+	}
+	switch {
+	case contains(newMinimum, newMaximum, oldMinimum, oldMaximum):
+		return "widened"
+	case contains(oldMinimum, oldMaximum, newMinimum, newMaximum):
+		return "narrowed"
+	default:
+		return "changed"
+	}
+}
+
+// This private function returns the notation for the specified limits of a
+// cardinality.
+func describeLimits(minimum int, maximum int) string {
+	if minimum == 1 && maximum == 1 {
+		// The default cardinality has no notation of its own.
+		return "{1}"
+	}
+	return keyOfLimits(minimum, maximum)
+}
+
+// This private function returns the first and last characters of the specified
+// glyph.
+func getRunes(glyph GlyphLike) (first int, last int) {
+	first = int(unquoteCharacter(glyph.GetFirst()))
+	last = first
+	if len(glyph.GetLast()) > 0 {
+		last = int(unquoteCharacter(glyph.GetLast()))
+	}
+	return first, last
+}
+
+// This private function returns the canonical notation for the specified
+// alternative ignoring any note and layout.
+func keyOfAlternative(alternative AlternativeLike) string {
+	var keys []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		keys = append(keys, keyOfFactor(iterator.GetNext()))
+	}
+	return sts.Join(keys, " ")
+}
+
+// This private function returns the canonical notation for the specified
+// assertion ignoring any notes and layout.
+func keyOfAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return element.GetIntrinsic() + element.GetLiteral() + element.GetName()
+	case glyph != nil && len(glyph.GetLast()) > 0:
+		return glyph.GetFirst() + ".." + glyph.GetLast()
+	case glyph != nil:
+		return glyph.GetFirst()
+	case precedence != nil:
+		var keys []string
+		var iterator = precedence.GetExpression().GetAlternatives().GetIterator()
+		for iterator.HasNext() {
+			keys = append(keys, keyOfAlternative(iterator.GetNext()))
+		}
+		return "(" + sts.Join(keys, " | ") + ")"
+	default:
+		panic("Attempted to describe an empty assertion.")
+	}
+}
+
+// This private function returns the canonical notation for the specified
+// factor.
+func keyOfFactor(factor FactorLike) string {
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	return keyOfPredicate(factor.GetPredicate()) + keyOfLimits(minimum, maximum)
+}
+
+// This private function returns the canonical notation for the specified
+// limits of a cardinality.
+func keyOfLimits(minimum int, maximum int) string {
+	switch {
+	case minimum == 1 && maximum == 1:
+		return ""
+	case minimum == 0 && maximum == 1:
+		return "?"
+	case minimum == 0 && maximum < 0:
+		return "*"
+	case minimum == 1 && maximum < 0:
+		return "+"
+	case maximum < 0:
+		return "{" + stc.Itoa(minimum) + "..}"
+	case minimum == maximum:
+		return "{" + stc.Itoa(minimum) + "}"
+	default:
+		return "{" + stc.Itoa(minimum) + ".." + stc.Itoa(maximum) + "}"
+	}
+}
+
+// This private function returns the canonical notation for the specified
+// predicate.
+func keyOfPredicate(predicate PredicateLike) string {
+	var key = keyOfAssertion(predicate.GetAssertion())
+	if predicate.IsInverted() {
+		key = "~" + key
+	}
+	return key
+}
+
+// This private function returns the definitions in the specified grammar in
+// the order that they are defined.
+func orderDefinitions(grammar GrammarLike) []DefinitionLike {
+	var definitions []DefinitionLike
+	var iterator = grammar.GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions
+}
+
+// This private function visits each unmatched pair of old and new indices in
+// the specified alignment of keys.  Runs of unmatched keys between two matched
+// keys are paired off in order and any key left over is paired with -1.
+func visitAlignment(matches []int, size int, visit func(oldIndex int, newIndex int)) {
+	var i, j = 0, 0
+	for i <= len(matches) {
+		var next, anchor = len(matches), size
+		for index := i; index < len(matches); index++ {
+			if matches[index] >= 0 {
+				next, anchor = index, matches[index]
+				break
+			}
+		}
+		var removed, added = next - i, anchor - j
+		for index := 0; index < max(removed, added); index++ {
+			var oldIndex, newIndex = -1, -1
+			if index < removed {
+				oldIndex = i + index
+			}
+			if index < added {
+				newIndex = j + index
+			}
+			visit(oldIndex, newIndex)
+		}
+		i, j = next+1, anchor+1
+	}
+}
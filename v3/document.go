@@ -48,6 +48,7 @@ func (c *documentClass_) FromGrammar(
 
 type document_ struct {
 	grammar GrammarLike
+	span    SpanLike
 }
 
 // Public Interface
@@ -56,9 +57,17 @@ func (v *document_) GetGrammar() GrammarLike {
 	return v.grammar
 }
 
+func (v *document_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *document_) SetGrammar(grammar GrammarLike) {
 	if grammar == nil {
 		panic("A grammar within a document cannot be nil.")
 	}
 	v.grammar = grammar
 }
+
+func (v *document_) SetSpan(span SpanLike) {
+	v.span = span
+}
@@ -74,6 +74,7 @@ type element_ struct {
 	intrinsic string
 	literal   string
 	name      string
+	span      SpanLike
 }
 
 // Public Interface
@@ -90,6 +91,10 @@ func (v *element_) GetName() string {
 	return v.name
 }
 
+func (v *element_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *element_) SetIntrinsic(intrinsic string) {
 	if len(intrinsic) < 1 {
 		var message = fmt.Sprintf("An invalid intrinsic was found:\n    %v\n", intrinsic)
@@ -113,3 +118,7 @@ func (v *element_) SetName(name string) {
 	}
 	v.name = name
 }
+
+func (v *element_) SetSpan(span SpanLike) {
+	v.span = span
+}
@@ -0,0 +1,245 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type parseErrorClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var parseErrorClass = &parseErrorClass_{
+	// This class does not initialize any constants.
+}
+
+// Private Class Constructors
+
+// This private class constructor creates a new parse error describing a token
+// that was received by the parser when it was expecting something else.
+func (c *parseErrorClass_) fromUnexpectedToken(
+	document string,
+	token *token_,
+	expected string,
+	rules ...string,
+) *parseError_ {
+	return c.fromUnexpectedInput(document, token, expected, grammar, rules...)
+}
+
+// This private class constructor creates a new parse error describing source
+// text that did not match the grammar being interpreted.  The definitions map
+// each rule symbol to the formatted expression that defines it.
+func (c *parseErrorClass_) fromUnexpectedInput(
+	document string,
+	token *token_,
+	expected string,
+	definitions map[string]string,
+	rules ...string,
+) *parseError_ {
+	var parseError = &parseError_{
+		document: document,
+		expected: expected,
+		grammar:  definitions,
+		rules:    col.ListClass[string]().FromArray(rules),
+		token:    token,
+	}
+	parseError.message = fmt.Sprintf(
+		"An unexpected %v token %q was received, was expecting '%v' from: %v",
+		token.GetType(),
+		token.GetValue(),
+		expected,
+		sts.Join(rules, ", "),
+	)
+	parseError.colored = parseError.formatContext(
+		"An unexpected token was received by the parser",
+	)
+	parseError.colored += parseError.formatRules()
+	return parseError
+}
+
+// This private class constructor creates a new parse error describing a symbol
+// that has already been defined in the grammar.
+func (c *parseErrorClass_) fromDuplicateSymbol(
+	document string,
+	token *token_,
+	existing string,
+) *parseError_ {
+	var parseError = &parseError_{
+		document: document,
+		rules:    col.ListClass[string]().Empty(),
+		token:    token,
+	}
+	parseError.message = fmt.Sprintf(
+		"The symbol %v has already been defined in this grammar.",
+		token.GetValue(),
+	)
+	parseError.colored = parseError.formatContext(
+		"An unexpected token was received by the parser",
+	)
+	parseError.colored += "This symbol has already been defined in this grammar:\n"
+	parseError.colored += "    " + existing + "\n"
+	return parseError
+}
+
+// This private class constructor creates a new parse error describing a name
+// that is referenced by the grammar but never defined.  The token is the first
+// reference to the name.
+func (c *parseErrorClass_) fromMissingDefinition(
+	document string,
+	token *token_,
+) *parseError_ {
+	var parseError = &parseError_{
+		document: document,
+		rules:    col.ListClass[string]().Empty(),
+		token:    token,
+	}
+	parseError.message = fmt.Sprintf(
+		"The grammar is missing a definition for name: %v",
+		token.GetValue(),
+	)
+	parseError.colored = parseError.message + "\n"
+	return parseError
+}
+
+// This private class constructor creates a new parse error describing an
+// invalid character that was found by the scanner.
+func (c *parseErrorClass_) fromInvalidToken(
+	document string,
+	token *token_,
+) *parseError_ {
+	var parseError = &parseError_{
+		document: document,
+		rules:    col.ListClass[string]().Empty(),
+		token:    token,
+	}
+	parseError.message = fmt.Sprintf(
+		"An invalid character %q was found by the scanner.",
+		token.GetValue(),
+	)
+	parseError.colored = parseError.formatContext(
+		"An invalid character was found by the scanner",
+	)
+	return parseError
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type parseError_ struct {
+	colored  string
+	document string
+	expected string
+	grammar  map[string]string // The definitions of the rules in the message.
+	message  string
+	rules    col.Sequential[string]
+	token    *token_
+}
+
+// Public Interface
+
+func (v *parseError_) Error() string {
+	return fmt.Sprintf("%v:%v: %v", v.GetLine(), v.GetPosition(), v.message)
+}
+
+func (v *parseError_) FormatColored() string {
+	return v.colored
+}
+
+func (v *parseError_) GetExpected() string {
+	return v.expected
+}
+
+func (v *parseError_) GetLine() int {
+	return v.token.GetLine()
+}
+
+func (v *parseError_) GetMessage() string {
+	return v.message
+}
+
+func (v *parseError_) GetPosition() int {
+	return v.token.GetPosition()
+}
+
+func (v *parseError_) GetRules() col.Sequential[string] {
+	return v.rules
+}
+
+func (v *parseError_) GetTokenType() string {
+	return v.token.GetType()
+}
+
+func (v *parseError_) GetTokenValue() string {
+	return v.token.GetValue()
+}
+
+// Private Interface
+
+// This private class method returns the specified header followed by a
+// colored rendering of the lines in the document surrounding the offending
+// token.
+func (v *parseError_) formatContext(header string) string {
+	var token = v.token
+	var message = fmt.Sprintf(
+		"%v: %v\n",
+		header,
+		token,
+	)
+	var line = token.GetLine()
+	var lines = sts.Split(v.document, "\n")
+
+	message += "\033[36m"
+	if line > 1 {
+		message += fmt.Sprintf("%04d: ", line-1) + string(lines[line-2]) + "\n"
+	}
+	message += fmt.Sprintf("%04d: ", line) + string(lines[line-1]) + "\n"
+
+	message += " \033[32m>>>─"
+	var count = 0
+	for count < token.GetPosition() {
+		message += "─"
+		count++
+	}
+	message += "⌃\033[36m\n"
+
+	if line < len(lines) {
+		message += fmt.Sprintf("%04d: ", line+1) + string(lines[line]) + "\n"
+	}
+	message += "\033[0m\n"
+
+	return message
+}
+
+// This private class method returns a colored rendering of the grammatical
+// rules that define what the parser was expecting.
+func (v *parseError_) formatRules() string {
+	var message = "Was expecting '" + v.expected + "' from:\n"
+	var iterator = v.rules.GetIterator()
+	for iterator.HasNext() {
+		var symbol = iterator.GetNext()
+		message += fmt.Sprintf(
+			"  \033[32m%v: \033[33m%v\033[0m\n\n",
+			symbol,
+			v.grammar[symbol],
+		)
+	}
+	return message
+}
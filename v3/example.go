@@ -0,0 +1,82 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type exampleClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var exampleClass = &exampleClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func ExampleClass() ExampleClassLike {
+	return exampleClass
+}
+
+// Public Class Constructors
+
+func (c *exampleClass_) FromSource(
+	source string,
+	isValid bool,
+	description string,
+) ExampleLike {
+	var example = &example_{
+		description: description,
+		isValid:     isValid,
+		source:      source,
+	}
+	return example
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type example_ struct {
+	description string // What the example covers.
+	isValid     bool
+	source      string
+}
+
+// Public Interface
+
+func (v *example_) GetDescription() string {
+	return v.description
+}
+
+func (v *example_) GetSource() string {
+	return v.source
+}
+
+func (v *example_) IsValid() bool {
+	return v.isValid
+}
+
+func (v *example_) String() string {
+	var verdict = "Invalid"
+	if v.isValid {
+		verdict = "Valid"
+	}
+	return fmt.Sprintf("%v: %v: %q", verdict, v.description, v.source)
+}
@@ -0,0 +1,481 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	slc "slices"
+	sts "strings"
+	uni "unicode"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type exemplifierClass_ struct {
+	intrinsics map[string]string
+}
+
+// Private Class Namespace Reference
+
+var exemplifierClass = &exemplifierClass_{
+	// The shortest text matched by each intrinsic.
+	intrinsics: map[string]string{
+		"ANY":     "a",
+		"CONTROL": "\t",
+		"DIGIT":   "0",
+		"EOF":     "",
+		"EOL":     "\n",
+		"ESCAPE":  `\n`,
+		"LOWER":   "a",
+		"UPPER":   "A",
+	},
+}
+
+// Public Class Namespace Access
+
+func ExemplifierClass() ExemplifierClassLike {
+	return exemplifierClass
+}
+
+// Public Class Constructors
+
+func (c *exemplifierClass_) FromDocument(document DocumentLike) ExemplifierLike {
+	var sampler = SamplerClass().FromDocument(document).(*sampler_)
+	var exemplifier = &exemplifier_{
+		document: document,
+		sampler:  sampler,
+	}
+	return exemplifier
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type exemplifier_ struct {
+	document     DocumentLike
+	examples     col.ListLike[ExampleLike]
+	forced       map[any]int // The alternatives and counts that must be generated.
+	interpreter  InterpreterLike
+	isToken      bool             // Whether or not a token is being generated.
+	name         string           // The name of the start definition.
+	paths        map[string][]any // The nodes that lead to each definition.
+	replacements map[PredicateLike]string
+	sampler      *sampler_ // Provides the heights and matching of definitions.
+	sources      map[string]bool
+}
+
+// Public Interface
+
+func (v *exemplifier_) GenerateExamples(symbol string) (
+	examples col.Sequential[ExampleLike],
+	err error,
+) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			err = fmt.Errorf("The examples for %v could not be generated: %v", symbol, message)
+		}
+	}()
+	v.interpreter = InterpreterClass().FromDocumentAndSymbol(v.document, symbol)
+	v.name = sts.TrimPrefix(symbol, "$")
+	if v.sampler.heights[v.name] == unreachable_ {
+		panic("The definition can never be completed without recursing forever.")
+	}
+	v.examples = col.ListClass[ExampleLike]().Empty()
+	v.sources = make(map[string]bool)
+	v.findPaths()
+	v.addExample(nil, nil, true, fmt.Sprintf("$%v: the shortest sentence", v.name))
+	var iterator = v.document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil {
+			continue
+		}
+		var symbol = definition.GetSymbol()
+		var path, ok = v.paths[symbol[1:]]
+		if ok {
+			v.coverExpression(symbol, definition.GetExpression(), path)
+		}
+	}
+	examples = v.examples
+	return examples, err
+}
+
+// Private Interface
+
+// This private class method generates a sentence for the start definition
+// that includes the forced alternatives, counts and replacements, and appends
+// it as an example.  The example is dropped if its sentence duplicates that of
+// an earlier example or if the interpreter disagrees about whether or not it is
+// valid, for example when a removed factor is supplied by a later one.
+func (v *exemplifier_) addExample(
+	forced map[any]int,
+	replacements map[PredicateLike]string,
+	isValid bool,
+	description string,
+) {
+	if forced == nil {
+		forced = make(map[any]int)
+	}
+	if replacements == nil {
+		replacements = make(map[PredicateLike]string)
+	}
+	v.forced = forced
+	v.replacements = replacements
+	v.isToken = false
+	var source = v.generateName(v.name)
+	if v.sources[source] {
+		return
+	}
+	var _, err = v.interpreter.ParseSource(source)
+	if (err == nil) != isValid {
+		return
+	}
+	v.sources[source] = true
+	v.examples.AppendValue(ExampleClass().FromSource(source, isValid, description))
+}
+
+// This private class method appends an example for each alternative of the
+// specified expression and covers each of its factors.
+func (v *exemplifier_) coverExpression(
+	symbol string,
+	expression ExpressionLike,
+	path []any,
+) {
+	var alternatives = expression.GetAlternatives().AsArray()
+	for index, alternative := range alternatives {
+		var alternativePath = extendPath(path, alternative)
+		if len(alternatives) > 1 {
+			var description = fmt.Sprintf("%v: alternative %v of %v", symbol, index+1, len(alternatives))
+			v.addExample(v.forcePath(alternativePath), nil, true, description)
+		}
+		var iterator = alternative.GetFactors().GetIterator()
+		for iterator.HasNext() {
+			v.coverFactor(symbol, iterator.GetNext(), alternativePath)
+		}
+	}
+}
+
+// This private class method appends examples for the boundaries of the
+// cardinality of the specified factor: the valid counts 0, 1, M and N that
+// lie within {M..N}, or M and M+1 when the maximum is unlimited, and the
+// invalid counts M-1 and N+1.  It also appends examples for the boundaries of
+// a glyph, and for the excluded characters of an inversion, and covers any
+// nested expression.
+func (v *exemplifier_) coverFactor(symbol string, factor FactorLike, path []any) {
+	var formatter = FormatterClass().Default().(*formatter_)
+	formatter.formatFactor(factor)
+	var formatted = formatter.getResult()
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	var candidates = []int{0, 1, minimum, maximum}
+	if maximum < 0 {
+		candidates = []int{minimum, minimum + 1}
+	}
+	var valid []int
+	for _, count := range candidates {
+		var isAllowed = count >= minimum && (maximum < 0 || count <= maximum)
+		if isAllowed && !slc.Contains(valid, count) {
+			valid = append(valid, count)
+		}
+	}
+	var invalid []int
+	if minimum > 0 {
+		invalid = append(invalid, minimum-1)
+	}
+	if maximum >= 0 {
+		invalid = append(invalid, maximum+1)
+	}
+	for _, count := range valid {
+		var forced = v.forcePath(path)
+		forced[factor] = count
+		var description = fmt.Sprintf("%v: %v with a count of %v", symbol, formatted, count)
+		v.addExample(forced, nil, true, description)
+	}
+	for _, count := range invalid {
+		var forced = v.forcePath(path)
+		forced[factor] = count
+		var description = fmt.Sprintf("%v: %v with a count of %v", symbol, formatted, count)
+		v.addExample(forced, nil, false, description)
+	}
+	if maximum == 0 {
+		return
+	}
+
+	var factorPath = extendPath(path, factor)
+	var predicate = factor.GetPredicate()
+	formatter.formatPredicate(predicate)
+	formatted = formatter.getResult()
+	var assertion = predicate.GetAssertion()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case predicate.IsInverted():
+		v.forced = make(map[any]int)
+		v.replacements = make(map[PredicateLike]string)
+		v.isToken = !isRuleName(symbol[1:])
+		var excluded = v.generateAssertion(assertion)
+		var replacements = map[PredicateLike]string{predicate: excluded}
+		var description = fmt.Sprintf("%v: %q for %v", symbol, excluded, formatted)
+		v.addExample(v.forcePath(factorPath), replacements, false, description)
+	case glyph != nil:
+		var first = unquoteCharacter(glyph.GetFirst())
+		var last = first
+		if len(glyph.GetLast()) > 0 {
+			last = unquoteCharacter(glyph.GetLast())
+		}
+		var characters = []rune{first, last, first - 1, last + 1}
+		for index, character := range characters {
+			var isValid = index < 2
+			if character < 0 || character > uni.MaxRune || index == 1 && last == first {
+				continue
+			}
+			var replacements = map[PredicateLike]string{predicate: string(character)}
+			var description = fmt.Sprintf("%v: %q for %v", symbol, string(character), formatted)
+			v.addExample(v.forcePath(factorPath), replacements, isValid, description)
+		}
+	case precedence != nil:
+		v.coverExpression(symbol, precedence.GetExpression(), factorPath)
+	}
+}
+
+// This private class method finds the shortest path of nodes leading from the
+// start definition to each definition that it references directly or
+// indirectly.  The names within inversions are not followed since they are
+// never generated.
+func (v *exemplifier_) findPaths() {
+	v.paths = map[string][]any{v.name: nil}
+	var queue = []string{v.name}
+	for len(queue) > 0 {
+		var name = queue[0]
+		queue = queue[1:]
+		var references []reference_
+		var expression = v.sampler.definitions[name].GetExpression()
+		findReferences(expression, nil, &references)
+		for _, reference := range references {
+			if _, ok := v.paths[reference.name]; !ok {
+				v.paths[reference.name] = append(extendPath(v.paths[name]), reference.path...)
+				queue = append(queue, reference.name)
+			}
+		}
+	}
+}
+
+// This private class method returns the forced alternatives and counts needed
+// to generate each node in the specified path at least once.
+func (v *exemplifier_) forcePath(path []any) map[any]int {
+	var forced = make(map[any]int)
+	for _, node := range path {
+		switch actual := node.(type) {
+		case AlternativeLike:
+			forced[actual] = 0
+		case FactorLike:
+			var minimum, _ = getLimits(actual.GetCardinality())
+			forced[actual] = max(minimum, 1)
+		}
+	}
+	return forced
+}
+
+func (v *exemplifier_) generateAlternative(alternative AlternativeLike) string {
+	var samples []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		samples = append(samples, v.generateFactor(iterator.GetNext()))
+	}
+	return joinSamples(samples, v.isToken)
+}
+
+func (v *exemplifier_) generateAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.generateElement(element)
+	case glyph != nil:
+		return string(unquoteCharacter(glyph.GetFirst()))
+	case precedence != nil:
+		return v.generateExpression(precedence.GetExpression())
+	default:
+		panic("Attempted to generate an empty assertion.")
+	}
+}
+
+func (v *exemplifier_) generateElement(element ElementLike) string {
+	var intrinsic = element.GetIntrinsic()
+	var literal = element.GetLiteral()
+	var name = element.GetName()
+	switch {
+	case len(intrinsic) > 0:
+		return exemplifierClass.intrinsics[intrinsic]
+	case len(literal) > 0:
+		return unquoteLiteral(literal)
+	case len(name) > 0:
+		return v.generateName(name)
+	default:
+		panic("Attempted to generate an empty element.")
+	}
+}
+
+// This private class method generates the forced alternative of the specified
+// expression if there is one, and otherwise the first of the alternatives that
+// can be completed with the fewest nested names.
+func (v *exemplifier_) generateExpression(expression ExpressionLike) string {
+	var alternatives = expression.GetAlternatives().AsArray()
+	for _, alternative := range alternatives {
+		if _, ok := v.forced[alternative]; ok {
+			delete(v.forced, alternative)
+			return v.generateAlternative(alternative)
+		}
+	}
+	var height = v.sampler.heightOfExpression(expression)
+	for _, alternative := range alternatives {
+		if v.sampler.heightOfAlternative(alternative) == height {
+			return v.generateAlternative(alternative)
+		}
+	}
+	panic("The expression can never be completed without recursing forever.")
+}
+
+// This private class method generates the predicate of the specified factor
+// the forced number of times, or otherwise the minimum number of times.
+func (v *exemplifier_) generateFactor(factor FactorLike) string {
+	var count, ok = v.forced[factor]
+	if ok {
+		delete(v.forced, factor)
+	} else {
+		count, _ = getLimits(factor.GetCardinality())
+	}
+	var samples []string
+	var predicate = factor.GetPredicate()
+	for i := 0; i < count; i++ {
+		samples = append(samples, v.generatePredicate(predicate))
+	}
+	return joinSamples(samples, v.isToken)
+}
+
+// This private class method generates the first thing that the specified
+// assertion does not match.  Within a token this is a single character and
+// within a rule it is a single terminal.
+func (v *exemplifier_) generateInversion(assertion AssertionLike) string {
+	if v.isToken {
+		for _, character := range samplerClass.alphabet {
+			if !v.sampler.matcher.matchesRune(assertion, character) {
+				return string(character)
+			}
+		}
+	} else {
+		for _, terminal := range v.sampler.terminals {
+			var text = v.generateTerminal(terminal.assertion)
+			if len(text) > 0 && !v.sampler.matchesText(assertion, text) {
+				return text
+			}
+		}
+	}
+	panic(fmt.Sprintf("Nothing could be found that does not match: %v",
+		describeAssertion(assertion)))
+}
+
+func (v *exemplifier_) generateName(name string) string {
+	var definition, ok = v.sampler.definitions[name]
+	if !ok {
+		panic(fmt.Sprintf("The grammar is missing a definition for name: %v", name))
+	}
+	var isToken = v.isToken
+	v.isToken = !isRuleName(name)
+	var text = v.generateExpression(definition.GetExpression())
+	v.isToken = isToken
+	return text
+}
+
+func (v *exemplifier_) generatePredicate(predicate PredicateLike) string {
+	var text, ok = v.replacements[predicate]
+	if ok {
+		delete(v.replacements, predicate)
+		return text
+	}
+	var assertion = predicate.GetAssertion()
+	if predicate.IsInverted() {
+		return v.generateInversion(assertion)
+	}
+	return v.generateAssertion(assertion)
+}
+
+// This private class method generates the shortest text of the specified
+// terminal as a token without using any of the forced alternatives, counts or
+// replacements.
+func (v *exemplifier_) generateTerminal(assertion AssertionLike) string {
+	var forced = v.forced
+	var isToken = v.isToken
+	var replacements = v.replacements
+	v.forced = make(map[any]int)
+	v.isToken = true
+	v.replacements = make(map[PredicateLike]string)
+	var text = v.generateAssertion(assertion)
+	v.forced = forced
+	v.isToken = isToken
+	v.replacements = replacements
+	return text
+}
+
+// PRIVATE TYPES
+
+// This private type records the path of nodes leading from the start of a
+// definition to a name that it references.
+type reference_ struct {
+	name string
+	path []any
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns a copy of the specified path extended with the
+// specified nodes.
+func extendPath(path []any, nodes ...any) []any {
+	var extended = make([]any, 0, len(path)+len(nodes))
+	extended = append(extended, path...)
+	return append(extended, nodes...)
+}
+
+// This private function appends to the references the first path to each name
+// that is referenced by the specified expression outside of any inversion.
+func findReferences(expression ExpressionLike, path []any, references *[]reference_) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors = alternative.GetFactors().GetIterator()
+		for factors.HasNext() {
+			var factor = factors.GetNext()
+			var predicate = factor.GetPredicate()
+			var _, maximum = getLimits(factor.GetCardinality())
+			if predicate.IsInverted() || maximum == 0 {
+				continue
+			}
+			var factorPath = extendPath(path, alternative, factor)
+			var assertion = predicate.GetAssertion()
+			var element = assertion.GetElement()
+			var precedence = assertion.GetPrecedence()
+			switch {
+			case element != nil && len(element.GetName()) > 0:
+				*references = append(*references, reference_{element.GetName(), factorPath})
+			case precedence != nil:
+				findReferences(precedence.GetExpression(), factorPath, references)
+			}
+		}
+	}
+}
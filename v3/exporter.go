@@ -0,0 +1,547 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+	uni "unicode"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type exporterClass_ struct {
+	intrinsicsISO    map[string]string
+	intrinsicsW3C    map[string]string
+	iso_             string
+	maximumExpansion int
+	maximumRange     int
+	w3c_             string
+}
+
+// Private Class Namespace Reference
+
+var exporterClass = &exporterClass_{
+	// The ISO 14977 definition of each intrinsic.  Special sequences describe
+	// the intrinsics informally since ISO 14977 cannot define them itself.
+	intrinsicsISO: map[string]string{
+		"ANY":     "? any character ?",
+		"CONTROL": "? any control character ?",
+		"DIGIT":   "? any decimal digit ?",
+		"EOF":     "? the end of the input ?",
+		"EOL":     "? the end of line character ?",
+		"ESCAPE":  "? any escape sequence ?",
+		"LOWER":   "? any lower case letter ?",
+		"UPPER":   "? any upper case letter ?",
+	},
+
+	// The W3C definition of each intrinsic.  Only ASCII letters and digits can
+	// be matched by W3C character classes so some intrinsics are approximated.
+	intrinsicsW3C: map[string]string{
+		"ANY":     "[#x0-#x10FFFF]",
+		"CONTROL": "[#x0-#x1F#x7F-#x9F]",
+		"DIGIT":   "[0-9]",
+		"EOL":     "#xA",
+		"ESCAPE": `'\' ([abfnrtv'"\] | 'x' [0-9a-f] [0-9a-f]` +
+			` | 'u' [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f]` +
+			` | 'U' [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f] [0-9a-f])`,
+		"LOWER": "[a-z]",
+		"UPPER": "[A-Z]",
+	},
+
+	iso_: "ISO",
+
+	// The largest repetition count that is expanded into copies of a factor.
+	maximumExpansion: 16,
+
+	// The largest range of characters that is expanded into alternatives.
+	maximumRange: 64,
+
+	w3c_: "W3C",
+}
+
+// Public Class Namespace Access
+
+func ExporterClass() ExporterClassLike {
+	return exporterClass
+}
+
+// Public Class Constants
+
+func (c *exporterClass_) GetISO() string {
+	return c.iso_
+}
+
+func (c *exporterClass_) GetW3C() string {
+	return c.w3c_
+}
+
+// Public Class Constructors
+
+func (c *exporterClass_) FromNotation(notation string) ExporterLike {
+	if notation != c.iso_ && notation != c.w3c_ {
+		panic(fmt.Sprintf("The notation is not supported: %v", notation))
+	}
+	var exporter = &exporter_{
+		notation: notation,
+	}
+	return exporter
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type exporter_ struct {
+	definition  DefinitionLike // The definition being exported.
+	diagnostics col.ListLike[DiagnosticLike]
+	intrinsics  col.SetLike[string] // The intrinsics used by the document.
+	notation    string
+	result      sts.Builder
+}
+
+// Public Interface
+
+func (v *exporter_) ExportDocument(document DocumentLike) string {
+	v.definition = nil
+	v.diagnostics = col.ListClass[DiagnosticLike]().Empty()
+	v.intrinsics = col.SetClass[string]().Empty()
+	v.result.Reset()
+	var statements = document.GetGrammar().GetStatements().GetIterator()
+	for statements.HasNext() {
+		var statement = statements.GetNext()
+		// Prepend a newline before all comments unless the first line is a comment.
+		if statements.GetSlot() > 1 && len(statement.GetComment()) > 0 {
+			v.result.WriteString("\n")
+		}
+		v.exportStatement(statement)
+	}
+	v.exportIntrinsics()
+	var result = v.result.String()
+	v.result.Reset()
+	return result
+}
+
+func (v *exporter_) GetDiagnostics() col.Sequential[DiagnosticLike] {
+	return v.diagnostics
+}
+
+func (v *exporter_) GetNotation() string {
+	return v.notation
+}
+
+// Private Interface
+
+func (v *exporter_) exportAlternative(alternative AlternativeLike) string {
+	var factors []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		var factor, _ = v.exportFactor(iterator.GetNext())
+		if len(factor) == 0 {
+			continue
+		}
+		factors = append(factors, factor)
+	}
+	return sts.Join(factors, v.getSeparator())
+}
+
+// This private class method returns the exported assertion and whether or not
+// it can be followed by a repetition operator without being grouped.
+func (v *exporter_) exportAssertion(assertion AssertionLike) (string, bool) {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.exportElement(element)
+	case glyph != nil:
+		return v.exportGlyph(glyph)
+	case precedence != nil:
+		var expression = v.exportExpression(precedence.GetExpression(), false)
+		return "(" + expression + ")", true
+	default:
+		panic("Attempted to export an empty assertion.")
+	}
+}
+
+// This private class method returns the body of a W3C character class that
+// matches the same characters as the specified assertion, if there is one.
+func (v *exporter_) exportCharacters(assertion AssertionLike) (string, bool) {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case glyph != nil && len(glyph.GetLast()) == 0:
+		return formatClassCharacter(unquoteCharacter(glyph.GetFirst())), true
+	case glyph != nil:
+		return formatClassCharacter(unquoteCharacter(glyph.GetFirst())) + "-" +
+			formatClassCharacter(unquoteCharacter(glyph.GetLast())), true
+	case element != nil && element.GetIntrinsic() == "CONTROL":
+		return "#x0-#x1F#x7F-#x9F", true
+	case element != nil && element.GetIntrinsic() == "EOL":
+		return "#xA", true
+	case element != nil && len(element.GetLiteral()) > 0:
+		var runes = []rune(unquoteLiteral(element.GetLiteral()))
+		return formatClassCharacter(runes[0]), len(runes) == 1
+	case precedence != nil:
+		var characters string
+		var alternatives = precedence.GetExpression().GetAlternatives().GetIterator()
+		for alternatives.HasNext() {
+			var factors = alternatives.GetNext().GetFactors()
+			var factor = factors.AsArray()[0]
+			if factors.GetSize() != 1 || factor.GetCardinality() != nil ||
+				factor.GetPredicate().IsInverted() {
+				return "", false
+			}
+			var more, ok = v.exportCharacters(factor.GetPredicate().GetAssertion())
+			if !ok {
+				return "", false
+			}
+			characters += more
+		}
+		return characters, true
+	default:
+		return "", false
+	}
+}
+
+// This private class method returns the exported text for the specified
+// comment.  The comment delimiters are replaced with those of the notation.
+func (v *exporter_) exportComment(comment string) string {
+	var text = sts.TrimSuffix(sts.TrimPrefix(comment, "!>"), "<!")
+	if v.notation == exporterClass.iso_ {
+		return "(*" + sts.ReplaceAll(text, "*)", "* )") + "*)"
+	}
+	return "/*" + sts.ReplaceAll(text, "*/", "* /") + "*/"
+}
+
+func (v *exporter_) exportDefinition(definition DefinitionLike) {
+	v.definition = definition
+	var name = definition.GetSymbol()[1:]
+	var expression = v.exportExpression(definition.GetExpression(), true)
+	if v.notation == exporterClass.iso_ {
+		v.result.WriteString(name + " = " + expression + " ;\n")
+	} else {
+		v.result.WriteString(name + " ::= " + expression + "\n")
+	}
+	v.definition = nil
+}
+
+func (v *exporter_) exportElement(element ElementLike) (string, bool) {
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case intrinsic == "EOF" && v.notation == exporterClass.w3c_:
+		v.reportLoss(element.GetSpan(), "There is no equivalent for the intrinsic EOF.")
+		return "/* EOF */", false
+	case len(intrinsic) > 0:
+		v.useIntrinsic(element.GetSpan(), intrinsic)
+		return intrinsic, true
+	case len(name) > 0:
+		return name, true
+	case len(literal) > 0:
+		return v.exportString(element.GetSpan(), unquoteLiteral(literal))
+	default:
+		panic("Attempted to export an empty element.")
+	}
+}
+
+// This private class method returns the exported alternatives of the specified
+// expression.  The alternatives of a multi-line expression at the top of a
+// definition are placed on separate lines.
+func (v *exporter_) exportExpression(expression ExpressionLike, isTop bool) string {
+	var separator = " | "
+	if isTop && expression.IsMultilined() {
+		separator = "\n    | "
+	}
+	var alternatives []string
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		var text = v.exportAlternative(alternative)
+		var note = alternative.GetNote()
+		if len(note) > 0 {
+			text += "  " + v.exportComment(" "+sts.TrimPrefix(note, "! ")+" ")
+		}
+		alternatives = append(alternatives, text)
+	}
+	return sts.Join(alternatives, separator)
+}
+
+// This private class method returns the exported factor and whether or not it
+// can be followed by a repetition operator without being grouped.  The
+// cardinality of the factor is mapped onto the repetitions supported by the
+// notation.
+func (v *exporter_) exportFactor(factor FactorLike) (string, bool) {
+	var predicate = factor.GetPredicate()
+	var text, isAtomic = v.exportPredicate(predicate)
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	if minimum == 1 && maximum == 1 {
+		return text, isAtomic
+	}
+	if maximum == 0 {
+		v.reportLoss(factor.GetSpan(), "A factor that is repeated zero times has been dropped.")
+		return "", true
+	}
+	var element = predicate.GetAssertion().GetElement()
+	if !predicate.IsInverted() && element != nil && element.GetIntrinsic() == "ANY" &&
+		minimum != maximum {
+		v.reportLoss(factor.GetSpan(), "The repetition of ANY is no longer the shortest possible match.")
+	}
+	var limit = exporterClass.maximumExpansion
+	if v.notation == exporterClass.w3c_ && (minimum > limit || maximum > limit) {
+		v.reportLoss(
+			factor.GetSpan(),
+			fmt.Sprintf("The repetition count is larger than %v and has been relaxed.", limit),
+		)
+		if minimum > 0 {
+			minimum = 1
+		}
+		maximum = -1
+	}
+	var group = text
+	if !isAtomic {
+		group = "(" + text + ")"
+	}
+	if v.notation == exporterClass.iso_ {
+		var inner = text
+		if !predicate.IsInverted() && sts.HasPrefix(text, "(") {
+			// ISO 14977 optional and repeated groups need no parentheses.
+			inner = text[1 : len(text)-1]
+		}
+		return v.repeatISO(inner, group, minimum, maximum), false
+	}
+	return repeatGroup(group, minimum, maximum)
+}
+
+func (v *exporter_) exportGlyph(glyph GlyphLike) (string, bool) {
+	var first = unquoteCharacter(glyph.GetFirst())
+	if len(glyph.GetLast()) == 0 {
+		return v.exportString(glyph.GetSpan(), string(first))
+	}
+	var last = unquoteCharacter(glyph.GetLast())
+	if v.notation == exporterClass.w3c_ {
+		return "[" + formatClassCharacter(first) + "-" + formatClassCharacter(last) + "]", true
+	}
+	if int(last-first) >= exporterClass.maximumRange {
+		v.reportLoss(glyph.GetSpan(), "The range of characters is only described informally.")
+		return "? " + glyph.GetFirst() + ".." + glyph.GetLast() + " ?", true
+	}
+	var characters []string
+	for character := first; character <= last; character++ {
+		var text, _ = v.exportString(glyph.GetSpan(), string(character))
+		characters = append(characters, text)
+	}
+	return "(" + sts.Join(characters, " | ") + ")", true
+}
+
+// This private class method appends a definition for each intrinsic used by
+// the document.  Intrinsics that cannot be defined exactly are approximated.
+func (v *exporter_) exportIntrinsics() {
+	if v.intrinsics.IsEmpty() {
+		return
+	}
+	var definitions = exporterClass.intrinsicsW3C
+	var format = "%v ::= %v\n"
+	if v.notation == exporterClass.iso_ {
+		definitions = exporterClass.intrinsicsISO
+		format = "%v = %v ;\n"
+	}
+	v.result.WriteString("\n" + v.exportComment(`
+    INTRINSIC DEFINITIONS
+    The following definitions describe the intrinsics used by the grammar.
+`) + "\n")
+	var iterator = v.intrinsics.GetIterator()
+	for iterator.HasNext() {
+		var intrinsic = iterator.GetNext()
+		v.result.WriteString(fmt.Sprintf(format, intrinsic, definitions[intrinsic]))
+	}
+}
+
+// This private class method returns the exported predicate and whether or not
+// it can be followed by a repetition operator without being grouped.  An
+// inverted predicate matches any single character that the predicate does not.
+func (v *exporter_) exportPredicate(predicate PredicateLike) (string, bool) {
+	var assertion = predicate.GetAssertion()
+	if !predicate.IsInverted() {
+		return v.exportAssertion(assertion)
+	}
+	if v.notation == exporterClass.w3c_ {
+		var characters, ok = v.exportCharacters(assertion)
+		if ok {
+			return "[^" + characters + "]", true
+		}
+	}
+	var text, _ = v.exportAssertion(assertion)
+	v.useIntrinsic(predicate.GetSpan(), "ANY")
+	return "(ANY - " + text + ")", true
+}
+
+func (v *exporter_) exportStatement(statement StatementLike) {
+	var comment = statement.GetComment()
+	if len(comment) > 0 {
+		v.result.WriteString(v.exportComment(comment) + "\n")
+		return
+	}
+	var definition = statement.GetDefinition()
+	if definition == nil {
+		panic("A statement must have either a comment or definition.")
+	}
+	v.exportDefinition(definition)
+}
+
+// This private class method returns the specified string as a sequence of one
+// or more quoted strings and whether or not the result is a single primary.
+// Neither notation supports escape sequences, so a string containing both
+// kinds of quotes is split and control characters are written separately.
+func (v *exporter_) exportString(span SpanLike, text string) (string, bool) {
+	var pieces []string
+	var current []rune
+	var flush = func() {
+		if len(current) > 0 {
+			var quote = `"`
+			if sts.ContainsRune(string(current), '"') {
+				quote = "'"
+			}
+			pieces = append(pieces, quote+string(current)+quote)
+			current = nil
+		}
+	}
+	for _, character := range text {
+		switch {
+		case !uni.IsPrint(character):
+			flush()
+			if v.notation == exporterClass.iso_ {
+				v.reportLoss(span, "A control character is only described informally.")
+				pieces = append(pieces, fmt.Sprintf("? U+%04X ?", character))
+			} else {
+				pieces = append(pieces, fmt.Sprintf("#x%X", character))
+			}
+		case character == '"' && sts.ContainsRune(string(current), '\''),
+			character == '\'' && sts.ContainsRune(string(current), '"'):
+			flush()
+			current = append(current, character)
+		default:
+			current = append(current, character)
+		}
+	}
+	flush()
+	return sts.Join(pieces, v.getSeparator()), len(pieces) == 1
+}
+
+func (v *exporter_) getSeparator() string {
+	if v.notation == exporterClass.iso_ {
+		return ", "
+	}
+	return " "
+}
+
+// This private class method records that the specified construct cannot be
+// exported exactly.
+func (v *exporter_) reportLoss(span SpanLike, message string) {
+	var symbol string
+	if v.definition != nil {
+		symbol = v.definition.GetSymbol()
+	}
+	var diagnostic = DiagnosticClass().FromKind(
+		DiagnosticClass().GetLossy(),
+		DiagnosticClass().GetWarning(),
+		symbol,
+		message,
+		span,
+	)
+	v.diagnostics.AppendValue(diagnostic)
+}
+
+// This private class method returns the ISO 14977 repetition of the specified
+// text.  ISO 14977 supports optional and repeated groups and a count of exact
+// repetitions.
+func (v *exporter_) repeatISO(text, group string, minimum, maximum int) string {
+	var parts []string
+	switch {
+	case minimum == 1:
+		parts = append(parts, group)
+	case minimum > 1:
+		parts = append(parts, fmt.Sprintf("%v * %v", minimum, group))
+	}
+	switch {
+	case maximum < 0:
+		parts = append(parts, "{"+text+"}")
+	case maximum-minimum == 1:
+		parts = append(parts, "["+text+"]")
+	case maximum > minimum:
+		parts = append(parts, fmt.Sprintf("%v * [%v]", maximum-minimum, text))
+	}
+	return sts.Join(parts, ", ")
+}
+
+// This private class method records that the specified intrinsic is used by
+// the document.  The first use of an intrinsic that can only be approximated
+// is reported.
+func (v *exporter_) useIntrinsic(span SpanLike, intrinsic string) {
+	if v.intrinsics.ContainsValue(intrinsic) {
+		return
+	}
+	v.intrinsics.AddValue(intrinsic)
+	switch {
+	case v.notation == exporterClass.iso_:
+		v.reportLoss(span, fmt.Sprintf("The intrinsic %v is only described informally.", intrinsic))
+	case intrinsic == "DIGIT", intrinsic == "LOWER", intrinsic == "UPPER":
+		v.reportLoss(span, fmt.Sprintf("The intrinsic %v only matches ASCII characters.", intrinsic))
+	}
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the specified character as it would appear
+// within a W3C character class.
+func formatClassCharacter(character rune) string {
+	if character < 0x80 && (uni.IsLetter(character) || uni.IsDigit(character)) {
+		return string(character)
+	}
+	return fmt.Sprintf("#x%X", character)
+}
+
+// This private function returns the repetition of the specified group using the
+// "?", "*" and "+" operators shared by the W3C and ANTLR notations, and whether
+// or not the result can itself be followed by a repetition operator.  Counted
+// repetitions are expanded into copies of the group.
+func repeatGroup(group string, minimum, maximum int) (string, bool) {
+	switch {
+	case maximum == 0:
+		panic("A factor cannot be repeated zero times.")
+	case minimum == 0 && maximum == 1:
+		return group + "?", true
+	case minimum == 0 && maximum < 0:
+		return group + "*", true
+	case minimum == 1 && maximum < 0:
+		return group + "+", true
+	}
+	var parts []string
+	for count := 1; count <= minimum; count++ {
+		parts = append(parts, group)
+	}
+	switch {
+	case maximum < 0:
+		parts[len(parts)-1] += "+"
+	case maximum > minimum:
+		var optional = group + "?"
+		for count := minimum + 1; count < maximum; count++ {
+			optional = "(" + group + " " + optional + ")?"
+		}
+		parts = append(parts, optional)
+	}
+	return sts.Join(parts, " "), len(parts) == 1
+}
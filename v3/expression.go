@@ -51,6 +51,7 @@ func (c *expressionClass_) FromAlternatives(alternatives col.Sequential[Alternat
 type expression_ struct {
 	alternatives col.Sequential[AlternativeLike]
 	isMultilined bool
+	span         SpanLike
 }
 
 // Public Interface
@@ -59,6 +60,10 @@ func (v *expression_) GetAlternatives() col.Sequential[AlternativeLike] {
 	return v.alternatives
 }
 
+func (v *expression_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *expression_) IsMultilined() bool {
 	return v.isMultilined
 }
@@ -73,3 +78,7 @@ func (v *expression_) SetAlternatives(alternatives col.Sequential[AlternativeLik
 func (v *expression_) SetMultilined(isMultilined bool) {
 	v.isMultilined = isMultilined
 }
+
+func (v *expression_) SetSpan(span SpanLike) {
+	v.span = span
+}
@@ -49,6 +49,7 @@ func (c *factorClass_) FromPredicate(
 type factor_ struct {
 	cardinality CardinalityLike
 	predicate   PredicateLike
+	span        SpanLike
 }
 
 // Public Interface
@@ -61,6 +62,10 @@ func (v *factor_) GetPredicate() PredicateLike {
 	return v.predicate
 }
 
+func (v *factor_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *factor_) SetCardinality(cardinality CardinalityLike) {
 	if cardinality == nil {
 		panic("An cardinality cannot be nil.")
@@ -74,3 +79,7 @@ func (v *factor_) SetPredicate(predicate PredicateLike) {
 	}
 	v.predicate = predicate
 }
+
+func (v *factor_) SetSpan(span SpanLike) {
+	v.span = span
+}
@@ -0,0 +1,989 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	gof "go/format"
+	tok "go/token"
+	stc "strconv"
+	sts "strings"
+	uni "unicode"
+	utf "unicode/utf8"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type generatorClass_ struct {
+	defaultSymbol string
+}
+
+// Private Class Namespace Reference
+
+var generatorClass = &generatorClass_{
+	defaultSymbol: "$document",
+}
+
+// Public Class Namespace Access
+
+func GeneratorClass() GeneratorClassLike {
+	return generatorClass
+}
+
+// Public Class Constructors
+
+func (c *generatorClass_) Default() GeneratorLike {
+	var generator = &generator_{}
+	return generator
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type generator_ struct {
+	compiler *compiler_
+	result   sts.Builder
+}
+
+// Public Interface
+
+func (v *generator_) GenerateANTLR(
+	grammarName string,
+	document DocumentLike,
+) (grammar string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			grammar = ""
+			err = fmt.Errorf("The grammar %v could not be generated: %v",
+				grammarName, message)
+		}
+	}()
+
+	// Make sure the grammar can be generated from the document.
+	if !tok.IsIdentifier(grammarName) || !uni.IsLetter([]rune(grammarName)[0]) {
+		panic(fmt.Sprintf("The grammar name %q is not a valid ANTLR grammar name.",
+			grammarName))
+	}
+	var diagnostics = ValidatorClass().Default().DiagnoseDocument(document)
+	var iterator = diagnostics.GetIterator()
+	for iterator.HasNext() {
+		var diagnostic = iterator.GetNext()
+		if diagnostic.GetSeverity() == DiagnosticClass().GetError() {
+			panic(fmt.Sprintf("The grammar is not valid: %v", diagnostic))
+		}
+	}
+
+	// Translate the document into an ANTLR4 combined grammar.
+	grammar = antlrClass.fromDocument(document).generateGrammar(grammarName)
+	return grammar, nil
+}
+
+func (v *generator_) GeneratePackage(
+	packageName string,
+	document DocumentLike,
+) (files col.CatalogLike[string, string], err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			files = nil
+			err = fmt.Errorf("The package %v could not be generated: %v",
+				packageName, message)
+		}
+	}()
+
+	// Make sure the package can be generated from the document.
+	if !tok.IsIdentifier(packageName) || sts.ToLower(packageName) != packageName {
+		panic(fmt.Sprintf("The package name %q is not a valid Go package name.",
+			packageName))
+	}
+	var diagnostics = ValidatorClass().Default().DiagnoseDocument(document)
+	var iterator = diagnostics.GetIterator()
+	for iterator.HasNext() {
+		var diagnostic = iterator.GetNext()
+		if diagnostic.GetSeverity() == DiagnosticClass().GetError() {
+			panic(fmt.Sprintf("The grammar is not valid: %v", diagnostic))
+		}
+	}
+	v.compiler = compilerClass.fromDocument(document)
+
+	// Generate each source file in the package.
+	var replacer = sts.NewReplacer(
+		"<Notice>", noticeTemplate_,
+		"<package>", packageName,
+		"<Terminals>", v.generateTerminals(document),
+		"<Rules>", v.generateRules(document),
+		"<start>", v.generateStart(document),
+	)
+	files = col.CatalogClass[string, string]().Empty()
+	files.SetValue("package.go", v.formatSource(replacer.Replace(packageTemplate_)))
+	files.SetValue("node.go", v.formatSource(replacer.Replace(nodeTemplate_)))
+	files.SetValue("token.go", v.formatSource(replacer.Replace(tokenTemplate_)))
+	files.SetValue("scanner.go", v.formatSource(replacer.Replace(scannerTemplate_)))
+	files.SetValue("parser.go", v.formatSource(replacer.Replace(parserTemplate_)))
+	return files, nil
+}
+
+// Private Interface
+
+func (v *generator_) appendString(s string) {
+	v.result.WriteString(s)
+}
+
+// This private class method returns the Go source code that parses the specified
+// alternative.
+func (v *generator_) generateAlternative(alternative AlternativeLike) {
+	v.appendString("func() bool {\nreturn ")
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		var factor = iterator.GetNext()
+		v.generateFactor(factor)
+		if iterator.HasNext() {
+			v.appendString(" &&\n")
+		}
+	}
+	v.appendString("\n},\n")
+}
+
+func (v *generator_) generateAssertion(assertion AssertionLike) {
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case precedence != nil:
+		v.generateExpression(precedence.GetExpression())
+	case element != nil && element.GetIntrinsic() == "EOF":
+		v.appendString("v.matchEOF(children)")
+	case element != nil && isRuleName(element.GetName()):
+		v.appendString("v.matchRule(v." + makeMethodName(element.GetName()) + ", children)")
+	default:
+		v.appendString("v.matchToken(" + quoteString(describeAssertion(assertion)) + ", children)")
+	}
+}
+
+func (v *generator_) generateExpression(expression ExpressionLike) {
+	v.appendString("v.matchAlternatives(children,\n")
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		v.generateAlternative(alternative)
+	}
+	v.appendString(")")
+}
+
+func (v *generator_) generateFactor(factor FactorLike) {
+	var cardinality = factor.GetCardinality()
+	if cardinality != nil {
+		var minimum, maximum = getLimits(cardinality)
+		v.appendString(fmt.Sprintf("v.repeat(%v, %v, func() bool {\nreturn ", minimum, maximum))
+	}
+	var predicate = factor.GetPredicate()
+	if predicate.IsInverted() {
+		var types []string
+		for _, tokenType := range v.invertedTypes(predicate.GetAssertion()) {
+			types = append(types, quoteString(tokenType))
+		}
+		v.appendString("v.matchInversion(children, " + sts.Join(types, ", ") + ")")
+	} else {
+		v.generateAssertion(predicate.GetAssertion())
+	}
+	if cardinality != nil {
+		v.appendString("\n})")
+	}
+}
+
+// This private class method returns the Go source code for the methods that
+// parse each rule definition in the specified document.
+func (v *generator_) generateRules(document DocumentLike) string {
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil || !isRuleName(definition.GetSymbol()[1:]) {
+			continue
+		}
+		var name = definition.GetSymbol()[1:]
+		var formatted = FormatterClass().Default().FormatDefinition(definition)
+		v.appendString("\n// This private class method parses the following rule definition:\n//\n")
+		for _, line := range sts.Split(formatted, "\n") {
+			v.appendString("//\t" + line + "\n")
+		}
+		v.appendString("func (v *parser_) " + makeMethodName(name) + "() (NodeLike, bool) {\n")
+		v.appendString("return v.parseRule(" + quoteString(name) + ", func(children *[]NodeLike) bool {\nreturn ")
+		v.generateExpression(definition.GetExpression())
+		v.appendString("\n})\n}\n")
+	}
+	return v.getResult()
+}
+
+// This private class method returns the name of the method that parses the
+// start rule, "$document" if it is defined, otherwise the first rule.
+func (v *generator_) generateStart(document DocumentLike) string {
+	var start string
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil || !isRuleName(definition.GetSymbol()[1:]) {
+			continue
+		}
+		var symbol = definition.GetSymbol()
+		if symbol == generatorClass.defaultSymbol || len(start) == 0 {
+			start = symbol
+		}
+	}
+	if len(start) == 0 {
+		panic("The grammar does not define any rules.")
+	}
+	return makeMethodName(start[1:])
+}
+
+// This private class method returns the Go source code that initializes the
+// token types and regular expressions used by the scanner.
+func (v *generator_) generateTerminals(document DocumentLike) string {
+	for _, terminal := range collectTerminals(document) {
+		var pattern, err = v.compiler.compileTerminal(terminal.assertion)
+		if err != nil {
+			panic(err.Error())
+		}
+		v.appendString(fmt.Sprintf("\n{\ntokenType: %v,\nmatcher: reg.MustCompile(%v),\n},",
+			quoteString(terminal.name), quoteString("^(?:"+pattern+")")))
+	}
+	v.appendString("\n")
+	return v.getResult()
+}
+
+func (v *generator_) formatSource(source string) string {
+	var bytes, err = gof.Source([]byte(source))
+	if err != nil {
+		panic(fmt.Sprintf("The generated source code is not valid: %v", err))
+	}
+	return string(bytes)
+}
+
+func (v *generator_) getResult() string {
+	var result = v.result.String()
+	v.result.Reset()
+	return result
+}
+
+// This private class method returns the token types that are excluded by an
+// inverted predicate within a rule definition.
+func (v *generator_) invertedTypes(assertion AssertionLike) []string {
+	var precedence = assertion.GetPrecedence()
+	if precedence == nil {
+		return []string{describeAssertion(assertion)}
+	}
+	var types []string
+	var iterator = precedence.GetExpression().GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var factors = iterator.GetNext().GetFactors()
+		var factor = factors.AsArray()[0]
+		if factors.GetSize() != 1 || factor.GetCardinality() != nil ||
+			factor.GetPredicate().IsInverted() {
+			panic("Only single tokens can be inverted within a rule.")
+		}
+		types = append(types, v.invertedTypes(factor.GetPredicate().GetAssertion())...)
+	}
+	return types
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the name of the method that parses the rule
+// with the specified name.
+func makeMethodName(name string) string {
+	var method = "parse"
+	var capitalize = true
+	for _, character := range name {
+		switch {
+		case character == '_':
+			capitalize = true
+		case capitalize:
+			method += string(uni.ToUpper(character))
+			capitalize = false
+		default:
+			method += string(character)
+		}
+	}
+	return method
+}
+
+// This private function returns the specified string as a Go string literal,
+// using a raw string literal when possible since it is easier to read.
+func quoteString(s string) string {
+	if sts.ContainsAny(s, "`\r\n") || !utf.ValidString(s) {
+		return stc.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// These private constants define the templates for the Go source files that
+// are generated.  Each template is formatted using the standard Go formatter
+// after the placeholders have been replaced.
+const (
+	noticeTemplate_ = `// Code generated by the CDSN generator. DO NOT EDIT.`
+
+	packageTemplate_ = `<Notice>
+
+/*
+This package file defines the INTERFACE to this package.  The package provides a
+parser for the notation defined by a Crater Dog Syntax Notation™ (CDSN) grammar.
+The scanner uses the token definitions in the grammar to generate the tokens
+that are processed by the parser.  The parser uses the rule definitions in the
+grammar to generate the concrete syntax tree for the source text.
+*/
+package <package>
+
+// PACKAGE ABSTRACTIONS
+
+// Abstract Types
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all node-class-like types.
+type NodeClassLike interface {
+	FromRule(
+		name string,
+		text string,
+		line int,
+		position int,
+		offset int,
+		children []NodeLike,
+	) NodeLike
+	FromToken(
+		name string,
+		text string,
+		line int,
+		position int,
+		offset int,
+	) NodeLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all node-like types.  A node-like type is a node in the concrete
+// syntax tree that results from parsing source text.
+type NodeLike interface {
+	GetChildren() []NodeLike
+	GetLine() int
+	GetName() string
+	GetOffset() int
+	GetPosition() int
+	GetText() string
+	IsToken() bool
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all parser-class-like types.
+type ParserClassLike interface {
+	Default() ParserLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all parser-like types.
+type ParserLike interface {
+	ParseSource(source string) (NodeLike, error)
+}
+`
+
+	nodeTemplate_ = `<Notice>
+
+package <package>
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type nodeClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var nodeClass = &nodeClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func NodeClass() NodeClassLike {
+	return nodeClass
+}
+
+// Public Class Constructors
+
+func (c *nodeClass_) FromRule(
+	name string,
+	text string,
+	line int,
+	position int,
+	offset int,
+	children []NodeLike,
+) NodeLike {
+	var node = &node_{
+		children: children,
+		line:     line,
+		name:     name,
+		offset:   offset,
+		position: position,
+		text:     text,
+	}
+	return node
+}
+
+func (c *nodeClass_) FromToken(
+	name string,
+	text string,
+	line int,
+	position int,
+	offset int,
+) NodeLike {
+	var node = &node_{
+		isToken:  true,
+		line:     line,
+		name:     name,
+		offset:   offset,
+		position: position,
+		text:     text,
+	}
+	return node
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type node_ struct {
+	children []NodeLike
+	isToken  bool
+	line     int
+	name     string // The rule or token name, or the literal text of a terminal.
+	offset   int    // The byte offset of the node in the source text.
+	position int
+	text     string // The source text that was matched by the node.
+}
+
+// Public Interface
+
+func (v *node_) GetChildren() []NodeLike {
+	return v.children
+}
+
+func (v *node_) GetLine() int {
+	return v.line
+}
+
+func (v *node_) GetName() string {
+	return v.name
+}
+
+func (v *node_) GetOffset() int {
+	return v.offset
+}
+
+func (v *node_) GetPosition() int {
+	return v.position
+}
+
+func (v *node_) GetText() string {
+	return v.text
+}
+
+func (v *node_) IsToken() bool {
+	return v.isToken
+}
+`
+
+	tokenTemplate_ = `<Notice>
+
+package <package>
+
+import (
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type tokenClass_ struct {
+	eof_   string
+	error_ string
+}
+
+// Private Class Namespace Reference
+
+var tokenClass = &tokenClass_{
+	eof_:   "EOF",
+	error_: "Error",
+}
+
+// Private Class Constructors
+
+func (c *tokenClass_) fromContext(
+	line int,
+	position int,
+	offset int,
+	types []string,
+	value string,
+) *token_ {
+	var token = &token_{
+		line:     line,
+		offset:   offset,
+		position: position,
+		types:    types,
+		value:    value,
+	}
+	return token
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type token_ struct {
+	line     int      // The line number of the token in the source text.
+	offset   int      // The byte offset of the token in the source text.
+	position int      // The position in the line of the first rune of the token.
+	types    []string // All token types that match the value of the token.
+	value    string
+}
+
+// Private Interface
+
+func (v *token_) hasType(tokenType string) bool {
+	for _, candidate := range v.types {
+		if candidate == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+// This private class method determines whether or not the token consists only
+// of spaces which are ignored by the parser unless they are expected.
+func (v *token_) isSpace() bool {
+	return v.types[0] != tokenClass.error_ && len(v.value) > 0 &&
+		len(sts.Trim(v.value, " ")) == 0
+}
+
+// This private class method determines whether or not the token consists only
+// of spaces and end-of-line characters.
+func (v *token_) isWhitespace() bool {
+	return v.types[0] != tokenClass.error_ && len(v.value) > 0 &&
+		len(sts.Trim(v.value, " \n")) == 0
+}
+`
+
+	scannerTemplate_ = `<Notice>
+
+package <package>
+
+import (
+	reg "regexp"
+	utf "unicode/utf8"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type scannerClass_ struct {
+	terminals []terminal_
+}
+
+// Private Class Namespace Reference
+
+var scannerClass = &scannerClass_{
+	// The terminals that may appear within the rule definitions.
+	terminals: []terminal_{<Terminals>},
+}
+
+// Private Class Constructors
+
+func (c *scannerClass_) fromSource(source string) *scanner_ {
+	var scanner = &scanner_{
+		line:     1,
+		position: 1,
+		source:   source,
+	}
+	return scanner
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type scanner_ struct {
+	line     int
+	offset   int
+	position int
+	source   string
+	tokens   []*token_
+}
+
+// Private Interface
+
+// This private class method scans the source text for the longest token at
+// each position.  Any spaces that are not part of a token are skipped.  The
+// scanning stops with an error token if no token matches.
+func (v *scanner_) scanTokens() []*token_ {
+	for v.offset < len(v.source) {
+		var text = v.source[v.offset:]
+		var length int
+		var types []string
+		for _, terminal := range scannerClass.terminals {
+			var match = terminal.matcher.FindString(text)
+			switch {
+			case len(match) > length:
+				length = len(match)
+				types = []string{terminal.tokenType}
+			case len(match) == length && length > 0:
+				types = append(types, terminal.tokenType)
+			}
+		}
+		if length == 0 {
+			if text[0] == ' ' {
+				v.advance(" ")
+				continue
+			}
+			var _, size = utf.DecodeRuneInString(text)
+			v.emitToken([]string{tokenClass.error_}, text[:size])
+			return v.tokens
+		}
+		v.emitToken(types, text[:length])
+	}
+	v.emitToken([]string{tokenClass.eof_}, "")
+	return v.tokens
+}
+
+func (v *scanner_) advance(text string) {
+	for _, character := range text {
+		v.position++
+		if character == '\n' {
+			v.line++
+			v.position = 1
+		}
+	}
+	v.offset += len(text)
+}
+
+func (v *scanner_) emitToken(types []string, value string) {
+	var token = tokenClass.fromContext(v.line, v.position, v.offset, types, value)
+	v.tokens = append(v.tokens, token)
+	v.advance(value)
+}
+
+// PRIVATE TYPES
+
+// This private type associates a token type with the regular expression that
+// matches it.
+type terminal_ struct {
+	matcher   *reg.Regexp
+	tokenType string
+}
+`
+
+	parserTemplate_ = `<Notice>
+
+package <package>
+
+import (
+	fmt "fmt"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type parserClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var parserClass = &parserClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func ParserClass() ParserClassLike {
+	return parserClass
+}
+
+// Public Class Constructors
+
+func (c *parserClass_) Default() ParserLike {
+	var parser = &parser_{}
+	return parser
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type parser_ struct {
+	expected []string // The token types expected at the failure.
+	failure  int      // The index of the farthest token that failed to match.
+	memos    map[memo_]*result_
+	next     int      // The index of the next token to be parsed.
+	rules    []string // The stack of rules currently being parsed.
+	source   string
+	stack    []string // The stack of rules at the failure.
+	tokens   []*token_
+}
+
+// Public Interface
+
+func (v *parser_) ParseSource(source string) (NodeLike, error) {
+	v.source = source
+	v.tokens = scannerClass.fromSource(source).scanTokens()
+	v.expected = nil
+	v.failure = -1
+	v.memos = make(map[memo_]*result_)
+	v.next = 0
+	v.rules = nil
+	v.stack = nil
+	var node, ok = v.<start>()
+	if ok {
+		var children []NodeLike
+		if v.matchEOF(&children) {
+			return node, nil
+		}
+	}
+	return nil, v.unexpectedToken()
+}
+
+// Private Interface
+
+// This private class method records that the specified token type was expected
+// at the specified token index.  Only the token types expected at the farthest
+// token index are kept.
+func (v *parser_) expect(index int, tokenType string) {
+	if index < v.failure {
+		return
+	}
+	if index > v.failure {
+		v.failure = index
+		v.expected = nil
+		v.stack = append([]string{}, v.rules...)
+	}
+	for _, existing := range v.expected {
+		if existing == tokenType {
+			return
+		}
+	}
+	v.expected = append(v.expected, tokenType)
+}
+
+// This private class method creates a node for the specified rule from the
+// nodes that were parsed starting with the specified token index.
+func (v *parser_) makeRule(name string, index int, children []NodeLike) NodeLike {
+	if len(children) == 0 {
+		var token = v.tokens[index]
+		return nodeClass.FromRule(name, "", token.line, token.position, token.offset, children)
+	}
+	var first = children[0]
+	var last = children[len(children)-1]
+	var text = v.source[first.GetOffset() : last.GetOffset()+len(last.GetText())]
+	return nodeClass.FromRule(
+		name,
+		text,
+		first.GetLine(),
+		first.GetPosition(),
+		first.GetOffset(),
+		children,
+	)
+}
+
+// This private class method attempts each alternative in order until one of
+// them matches.  Any nodes parsed by a failed alternative are discarded.
+func (v *parser_) matchAlternatives(
+	children *[]NodeLike,
+	alternatives ...func() bool,
+) bool {
+	var next = v.next
+	var count = len(*children)
+	for _, alternative := range alternatives {
+		if alternative() {
+			return true
+		}
+		v.next = next
+		*children = (*children)[:count]
+	}
+	return false
+}
+
+// This private class method matches the end of the source text.  Any trailing
+// spaces and end-of-line characters are ignored.
+func (v *parser_) matchEOF(children *[]NodeLike) bool {
+	var index = v.next
+	for v.tokens[index].isWhitespace() {
+		index++
+	}
+	var token = v.tokens[index]
+	if !token.hasType(tokenClass.eof_) {
+		v.expect(v.skipSpaces(v.next), tokenClass.eof_)
+		return false
+	}
+	var node = nodeClass.FromToken(tokenClass.eof_, "", token.line, token.position, token.offset)
+	*children = append(*children, node)
+	v.next = index
+	return true
+}
+
+// This private class method matches any single token that does not have one of
+// the specified token types.
+func (v *parser_) matchInversion(children *[]NodeLike, types ...string) bool {
+	var index = v.skipSpaces(v.next)
+	var token = v.tokens[index]
+	var inverted = token.types[0] == tokenClass.eof_ || token.types[0] == tokenClass.error_
+	for _, tokenType := range types {
+		inverted = inverted || token.hasType(tokenType)
+	}
+	if inverted {
+		v.expect(index, "~"+sts.Join(types, " | "))
+		return false
+	}
+	var node = nodeClass.FromToken(token.types[0], token.value, token.line, token.position, token.offset)
+	*children = append(*children, node)
+	v.next = index + 1
+	return true
+}
+
+func (v *parser_) matchRule(parse func() (NodeLike, bool), children *[]NodeLike) bool {
+	var node, ok = parse()
+	if ok {
+		*children = append(*children, node)
+	}
+	return ok
+}
+
+// This private class method matches the next token with the specified token
+// type.  Any spaces preceding the token are ignored.
+func (v *parser_) matchToken(tokenType string, children *[]NodeLike) bool {
+	var index = v.next
+	for !v.tokens[index].hasType(tokenType) {
+		if !v.tokens[index].isSpace() {
+			v.expect(index, tokenType)
+			return false
+		}
+		index++
+	}
+	var token = v.tokens[index]
+	var node = nodeClass.FromToken(tokenType, token.value, token.line, token.position, token.offset)
+	*children = append(*children, node)
+	v.next = index + 1
+	return true
+}
+
+// This private class method parses a rule using the specified function.  The
+// results are memoized, and a rule that is reentered at the same token index
+// (left recursion) fails.
+func (v *parser_) parseRule(
+	name string,
+	parse func(children *[]NodeLike) bool,
+) (NodeLike, bool) {
+	var key = memo_{index: v.next, name: name}
+	var result, ok = v.memos[key]
+	if ok {
+		if result.ok {
+			v.next = result.next
+		}
+		return result.node, result.ok
+	}
+	result = &result_{}
+	v.memos[key] = result
+	v.rules = append(v.rules, "$"+name)
+	var children []NodeLike
+	var matched = parse(&children)
+	v.rules = v.rules[:len(v.rules)-1]
+	if !matched {
+		v.next = key.index
+		return nil, false
+	}
+	result.node = v.makeRule(name, key.index, children)
+	result.next = v.next
+	result.ok = true
+	return result.node, true
+}
+
+// This private class method parses the specified number of instances of
+// something.  A maximum of -1 means unlimited.
+func (v *parser_) repeat(minimum int, maximum int, parse func() bool) bool {
+	var count = 0
+	for maximum < 0 || count < maximum {
+		var next = v.next
+		if !parse() {
+			break
+		}
+		count++
+		if v.next == next && count >= minimum {
+			// Don't loop forever matching nothing.
+			break
+		}
+	}
+	return count >= minimum
+}
+
+func (v *parser_) skipSpaces(index int) int {
+	for v.tokens[index].isSpace() {
+		index++
+	}
+	return index
+}
+
+// This private class method returns an error describing the farthest token
+// that could not be parsed.
+func (v *parser_) unexpectedToken() error {
+	if v.failure < 0 {
+		v.failure = v.next
+	}
+	var token = v.tokens[v.failure]
+	var rules = v.stack
+	if len(rules) > 2 {
+		rules = rules[len(rules)-2:]
+	}
+	return fmt.Errorf(
+		"%v:%v: An unexpected %v token %q was received, was expecting '%v' from: %v",
+		token.line,
+		token.position,
+		token.types[0],
+		token.value,
+		sts.Join(v.expected, " | "),
+		sts.Join(rules, ", "),
+	)
+}
+<Rules>
+// PRIVATE TYPES
+
+// This private type identifies the parsing of a rule at a token index.
+type memo_ struct {
+	index int
+	name  string
+}
+
+// This private type records the result of parsing a rule at a token index.
+type result_ struct {
+	next int
+	node NodeLike
+	ok   bool
+}
+`
+)
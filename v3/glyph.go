@@ -56,6 +56,7 @@ func (c *glyphClass_) FromRange(first, last string) GlyphLike {
 type glyph_ struct {
 	first string
 	last  string
+	span  SpanLike
 }
 
 // Public Interface
@@ -68,6 +69,10 @@ func (v *glyph_) GetLast() string {
 	return v.last
 }
 
+func (v *glyph_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *glyph_) SetFirst(first string) {
 	if len(first) < 1 {
 		panic("A glyph requires a first character.")
@@ -78,3 +83,7 @@ func (v *glyph_) SetFirst(first string) {
 func (v *glyph_) SetLast(last string) {
 	v.last = last
 }
+
+func (v *glyph_) SetSpan(span SpanLike) {
+	v.span = span
+}
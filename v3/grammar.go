@@ -11,7 +11,9 @@
 package cdsn
 
 import (
+	fmt "fmt"
 	col "github.com/craterdog/go-collection-framework/v3"
+	slc "slices"
 )
 
 // CLASS NAMESPACE
@@ -49,18 +51,260 @@ func (c *grammarClass_) FromStatements(statements col.Sequential[StatementLike])
 // Private Class Type Definition
 
 type grammar_ struct {
+	span       SpanLike
 	statements col.Sequential[StatementLike]
 }
 
 // Public Interface
 
+func (v *grammar_) Extract(
+	definition DefinitionLike,
+	alternative int,
+	first int,
+	last int,
+	symbol string,
+) {
+	var definitions = collectDefinitions(v)
+	if definition == nil || definitions[definition.GetSymbol()] != definition {
+		panic("The definition is not part of the grammar.")
+	}
+	var alternatives = definition.GetExpression().GetAlternatives().AsArray()
+	if alternative < 1 || alternative > len(alternatives) {
+		var message = fmt.Sprintf(
+			"The definition %v does not have an alternative %v.",
+			definition.GetSymbol(),
+			alternative,
+		)
+		panic(message)
+	}
+	var selected = alternatives[alternative-1]
+	var factors = selected.GetFactors().AsArray()
+	if first < 1 || first > last || last > len(factors) {
+		var message = fmt.Sprintf(
+			"The alternative %v does not have the factors %v..%v.",
+			alternative,
+			first,
+			last,
+		)
+		panic(message)
+	}
+	var name = checkSymbol(symbol)
+	var isRule = isRuleName(definition.GetSymbol()[1:])
+	if isRuleName(name) != isRule {
+		var kind = "token"
+		if isRule {
+			kind = "rule"
+		}
+		panic(fmt.Sprintf("The new symbol %v must name a %v.", symbol, kind))
+	}
+	if definitions[symbol] != nil {
+		panic(fmt.Sprintf("The new symbol is already defined: %v", symbol))
+	}
+
+	// Move the selected factors into the new definition.
+	var extracted = factors[first-1 : last]
+	var expression = makeExpression(extracted...)
+	var predicate = extracted[0].GetPredicate()
+	var precedence = predicate.GetAssertion().GetPrecedence()
+	var minimum, maximum = getLimits(extracted[0].GetCardinality())
+	if len(extracted) == 1 && precedence != nil && !predicate.IsInverted() &&
+		minimum == 1 && maximum == 1 {
+		// The parentheses around a lone precedence are not needed.
+		expression = precedence.GetExpression()
+	}
+	var keys = formatFactors(extracted)
+	var reference = makeFactor(makePredicate(name), 1, 1)
+	factors = slc.Replace(factors, first-1, last, reference)
+	selected.SetFactors(col.ListClass[FactorLike]().FromArray(factors))
+
+	// Replace any other occurrences of the same factors within the alternative.
+	var scope = col.ListClass[AlternativeLike]().FromArray([]AlternativeLike{selected})
+	replaceFactors(ExpressionClass().FromAlternatives(scope), keys, name)
+
+	// Insert the new definition after the definition it was extracted from.
+	var extraction = DefinitionClass().FromSymbolAndExpression(symbol, expression)
+	var statements []StatementLike
+	var iterator = v.statements.GetIterator()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		statements = append(statements, statement)
+		if statement.GetDefinition() == definition {
+			statements = append(statements, StatementClass().FromDefinition(extraction))
+		}
+	}
+	v.SetStatements(col.ListClass[StatementLike]().FromArray(statements))
+}
+
+func (v *grammar_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *grammar_) GetStatements() col.Sequential[StatementLike] {
 	return v.statements
 }
 
+func (v *grammar_) Inline(symbol string) {
+	var definitions = collectDefinitions(v)
+	var definition = definitions[symbol]
+	if definition == nil {
+		panic(fmt.Sprintf("The symbol is not defined: %v", symbol))
+	}
+	var name = symbol[1:]
+	if slc.Contains(collectNames(definition), name) {
+		panic(fmt.Sprintf("The symbol %v cannot be inlined since its definition is recursive.", symbol))
+	}
+
+	// Check all references before any of them are changed.
+	var callers []DefinitionLike
+	var statements []StatementLike
+	var array = v.statements.AsArray()
+	for index, statement := range array {
+		var caller = statement.GetDefinition()
+		if caller == definition {
+			var isLast = index+1 == len(array) || array[index+1].GetDefinition() == nil
+			for isLast && len(statements) > 0 && statements[len(statements)-1].GetDefinition() == nil {
+				// The comments that only describe the definition are removed.
+				statements = statements[:len(statements)-1]
+			}
+			continue
+		}
+		statements = append(statements, statement)
+		if caller == nil || !slc.Contains(collectNames(caller), name) {
+			continue
+		}
+		if !isRuleName(name) && isRuleName(caller.GetSymbol()[1:]) {
+			// Any spaces between the factors of a token would no longer be
+			// significant within the rule.
+			var message = fmt.Sprintf(
+				"The token %v cannot be inlined into the rule %v.",
+				symbol,
+				caller.GetSymbol(),
+			)
+			panic(message)
+		}
+		callers = append(callers, caller)
+	}
+	if len(callers) == 0 {
+		panic(fmt.Sprintf("The symbol %v cannot be inlined since it is not referenced.", symbol))
+	}
+
+	// Replace each reference with the expression and remove the definition.
+	for _, caller := range callers {
+		inlineReferences(caller.GetExpression(), name, definition.GetExpression())
+	}
+	v.SetStatements(col.ListClass[StatementLike]().FromArray(statements))
+}
+
+func (v *grammar_) SetSpan(span SpanLike) {
+	v.span = span
+}
+
 func (v *grammar_) SetStatements(statements col.Sequential[StatementLike]) {
 	if statements == nil || statements.IsEmpty() {
 		panic("An grammar must have at least one statement.")
 	}
 	v.statements = statements
 }
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the formatted text of each specified factor.
+func formatFactors(factors []FactorLike) []string {
+	var formatter = FormatterClass().Default().(*formatter_)
+	var keys []string
+	for _, factor := range factors {
+		formatter.formatFactor(factor)
+		keys = append(keys, formatter.getResult())
+	}
+	return keys
+}
+
+// This private function replaces each factor within the specified expression
+// that references the specified name with a copy of the specified expression.
+func inlineReferences(expression ExpressionLike, name string, body ExpressionLike) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors []FactorLike
+		var iterator = alternative.GetFactors().GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			var assertion = factor.GetPredicate().GetAssertion()
+			var precedence = assertion.GetPrecedence()
+			if precedence != nil {
+				inlineReferences(precedence.GetExpression(), name, body)
+			}
+			var element = assertion.GetElement()
+			if element == nil || element.GetName() != name {
+				factors = append(factors, factor)
+				continue
+			}
+			factors = append(factors, inlineFactor(factor, body)...)
+		}
+		alternative.SetFactors(col.ListClass[FactorLike]().FromArray(factors))
+	}
+}
+
+// This private function returns the factors that replace the specified factor
+// when a copy of the specified expression is inlined into it.  A precedence is
+// only used when the factors cannot be spliced in directly.
+func inlineFactor(factor FactorLike, body ExpressionLike) []FactorLike {
+	var expression = copyExpression(body)
+	var alternatives = expression.GetAlternatives().AsArray()
+	var hasNote bool
+	for _, alternative := range alternatives {
+		hasNote = hasNote || len(alternative.GetNote()) > 0
+	}
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	var isInverted = factor.GetPredicate().IsInverted()
+	if len(alternatives) == 1 && !hasNote {
+		var factors = alternatives[0].GetFactors().AsArray()
+		if minimum == 1 && maximum == 1 && !isInverted {
+			return factors
+		}
+		if len(factors) == 1 {
+			var innerMinimum, innerMaximum = getLimits(factors[0].GetCardinality())
+			var predicate = factors[0].GetPredicate()
+			if innerMinimum == 1 && innerMaximum == 1 && !(isInverted && predicate.IsInverted()) {
+				predicate.SetInverted(isInverted || predicate.IsInverted())
+				return []FactorLike{makeFactor(predicate, minimum, maximum)}
+			}
+		}
+	}
+	if hasNote {
+		// A note runs to the end of its line so each alternative needs its own.
+		expression.SetMultilined(true)
+	}
+	var precedence = PrecedenceClass().FromExpression(expression)
+	var assertion = AssertionClass().FromPrecedence(precedence)
+	var predicate = PredicateClass().FromAssertion(assertion, isInverted)
+	return []FactorLike{makeFactor(predicate, minimum, maximum)}
+}
+
+// This private function replaces each sequence of factors within the specified
+// expression that formats the same as the specified keys with a reference to
+// the specified name.  Inverted precedences are left unchanged since they
+// cannot contain a sequence of factors.
+func replaceFactors(expression ExpressionLike, keys []string, name string) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors = alternative.GetFactors().AsArray()
+		var replaced []FactorLike
+		for index := 0; index < len(factors); index++ {
+			var end = index + len(keys)
+			if end <= len(factors) && slc.Equal(formatFactors(factors[index:end]), keys) {
+				replaced = append(replaced, makeFactor(makePredicate(name), 1, 1))
+				index = end - 1
+				continue
+			}
+			var predicate = factors[index].GetPredicate()
+			var precedence = predicate.GetAssertion().GetPrecedence()
+			if precedence != nil && !predicate.IsInverted() {
+				replaceFactors(precedence.GetExpression(), keys, name)
+			}
+			replaced = append(replaced, factors[index])
+		}
+		alternative.SetFactors(col.ListClass[FactorLike]().FromArray(replaced))
+	}
+}
@@ -0,0 +1,872 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	stc "strconv"
+	sts "strings"
+	uni "unicode"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type importerClass_ struct {
+	abnf_     string
+	coreRules string
+}
+
+// Private Class Namespace Reference
+
+var importerClass = &importerClass_{
+	abnf_: "ABNF",
+
+	// The core rules defined in appendix B of RFC 5234.  They are added to an
+	// imported grammar when they are used but not defined by it.  The letters
+	// of HEXDIG are case insensitive like the ABNF strings that define them.
+	coreRules: `ALPHA = %x41-5A / %x61-7A
+BIT = "0" / "1"
+CHAR = %x01-7F
+CR = %x0D
+CRLF = CR LF
+CTL = %x00-1F / %x7F
+DIGIT = %x30-39
+DQUOTE = %x22
+HEXDIG = DIGIT / %x41-46 / %x61-66
+HTAB = %x09
+LF = %x0A
+LWSP = *(WSP / CRLF WSP)
+OCTET = %x00-FF
+SP = %x20
+VCHAR = %x21-7E
+WSP = SP / HTAB
+`,
+}
+
+// Public Class Namespace Access
+
+func ImporterClass() ImporterClassLike {
+	return importerClass
+}
+
+// Public Class Constants
+
+func (c *importerClass_) GetABNF() string {
+	return c.abnf_
+}
+
+// Public Class Constructors
+
+func (c *importerClass_) FromNotation(notation string) ImporterLike {
+	if notation != c.abnf_ {
+		panic(fmt.Sprintf("The notation is not supported: %v", notation))
+	}
+	var importer = &importer_{
+		notation: notation,
+	}
+	return importer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type importer_ struct {
+	definitions col.CatalogLike[string, DefinitionLike] // Keyed by lower case name.
+	diagnostics col.ListLike[DiagnosticLike]
+	isCore      bool // Whether or not the core rules are being imported.
+	locations   []LocationLike
+	names       col.CatalogLike[string, string] // The ABNF name for each key.
+	notation    string
+	notes       col.CatalogLike[int, string] // The comments within the rule.
+	position    int
+	runes       []rune
+	statements  col.ListLike[StatementLike]
+	symbol      string // The ABNF name of the rule being imported.
+}
+
+// Public Interface
+
+func (v *importer_) GetDiagnostics() col.Sequential[DiagnosticLike] {
+	return v.diagnostics
+}
+
+func (v *importer_) GetNotation() string {
+	return v.notation
+}
+
+func (v *importer_) ImportSource(source string) (
+	document DocumentLike,
+	err error,
+) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			document = nil
+			err = fmt.Errorf("The %v grammar cannot be imported: %v", v.notation, message)
+		}
+	}()
+	v.definitions = col.CatalogClass[string, DefinitionLike]().Empty()
+	v.diagnostics = col.ListClass[DiagnosticLike]().Empty()
+	v.names = col.CatalogClass[string, string]().Empty()
+	v.statements = col.ListClass[StatementLike]().Empty()
+	v.isCore = false
+	v.importRules(source)
+	v.importCoreRules()
+	v.classifyRules()
+	var grammar = GrammarClass().FromStatements(v.statements)
+	document = DocumentClass().FromGrammar(grammar)
+	var diagnostics = ValidatorClass().Default().DiagnoseDocument(document).GetIterator()
+	for diagnostics.HasNext() {
+		var diagnostic = diagnostics.GetNext()
+		if diagnostic.GetSeverity() == DiagnosticClass().GetError() {
+			panic(fmt.Sprintf("The definition for %v is invalid: %v",
+				diagnostic.GetSymbol(), diagnostic.GetMessage()))
+		}
+	}
+	return document, nil
+}
+
+// Private Interface
+
+// This private class method determines which ABNF rules become CDSN token
+// definitions and which become rule definitions, and renames them accordingly.
+// A CDSN token cannot be recursive or refer to a rule so only the ABNF rules
+// that are recursive, or refer to a recursive rule, become rule definitions.
+// All other ABNF rules become token definitions which, like ABNF rules, match
+// every character including spaces.
+func (v *importer_) classifyRules() {
+	var keys = v.definitions.GetKeys().AsArray()
+	var references = col.CatalogClass[string, []string]().Empty()
+	for _, key := range keys {
+		references.SetValue(key, collectNames(v.definitions.GetValue(key)))
+	}
+
+	// Find the recursive rules.
+	var isRule = col.CatalogClass[string, bool]().Empty()
+	for _, key := range keys {
+		var visited = col.SetClass[string]().Empty()
+		var pending = append([]string{}, references.GetValue(key)...)
+		for len(pending) > 0 {
+			var current = pending[0]
+			pending = pending[1:]
+			if current == key {
+				isRule.SetValue(key, true)
+				break
+			}
+			if !visited.ContainsValue(current) {
+				visited.AddValue(current)
+				pending = append(pending, references.GetValue(current)...)
+			}
+		}
+	}
+
+	// Find the rules that refer to rules.
+	var changed = true
+	for changed {
+		changed = false
+		for _, key := range keys {
+			for _, reference := range references.GetValue(key) {
+				if isRule.GetValue(reference) && !isRule.GetValue(key) {
+					isRule.SetValue(key, true)
+					changed = true
+				}
+			}
+		}
+	}
+
+	// Rename the definitions and the references to them.
+	var renamer = &nameRenamer_{
+		VisitorLike: VisitorClass().Default(),
+		names:       col.CatalogClass[string, string]().Empty(),
+	}
+	var used = col.SetClass[string]().Empty()
+	for _, key := range keys {
+		var definition = v.definitions.GetValue(key)
+		v.symbol = v.names.GetValue(key)
+		var name = makeName(v.symbol, isRule.GetValue(key))
+		if used.ContainsValue(name) {
+			// Different ABNF names may map onto the same CDSN name.
+			var unique = name
+			for count := 2; used.ContainsValue(unique); count++ {
+				unique = name + "_" + stc.Itoa(count)
+			}
+			v.reportLoss(
+				definition.GetSpan(),
+				fmt.Sprintf("The rule %v was renamed %v since the name %v is already used.",
+					v.symbol, unique, name),
+			)
+			name = unique
+		}
+		used.AddValue(name)
+		renamer.names.SetValue(key, name)
+		definition.SetSymbol("$" + name)
+		if isRule.GetValue(key) && definition.GetSpan() != nil {
+			v.reportLoss(
+				definition.GetSpan(),
+				fmt.Sprintf("The rule %v is recursive, or uses a recursive rule, so spaces between its tokens are ignored.",
+					v.symbol),
+			)
+		}
+	}
+	var walker = WalkerClass().FromVisitor(renamer)
+	for _, key := range keys {
+		walker.WalkDefinition(v.definitions.GetValue(key))
+	}
+
+	// Refer to each definition by its new symbol within the diagnostics.
+	var diagnostics = v.diagnostics.AsArray()
+	v.diagnostics = col.ListClass[DiagnosticLike]().Empty()
+	for _, diagnostic := range diagnostics {
+		var symbol = diagnostic.GetSymbol()
+		var name = renamer.names.GetValue(symbol[1:])
+		if len(name) > 0 {
+			symbol = "$" + name
+		}
+		v.diagnostics.AppendValue(DiagnosticClass().FromKind(
+			diagnostic.GetKind(),
+			diagnostic.GetSeverity(),
+			symbol,
+			diagnostic.GetMessage(),
+			diagnostic.GetSpan(),
+		))
+	}
+}
+
+// This private class method returns the location of the current position in
+// the ABNF source.
+func (v *importer_) formatLocation() string {
+	var location = v.locations[v.position]
+	return fmt.Sprintf("line %v, position %v", location.GetLine(), location.GetPosition())
+}
+
+func (v *importer_) importAlternation() ExpressionLike {
+	var alternatives = col.ListClass[AlternativeLike]().Empty()
+	alternatives.AppendValue(v.importConcatenation())
+	for {
+		var position = v.position
+		v.skipSpace()
+		if !v.isNext('/') {
+			v.position = position
+			break
+		}
+		v.position++
+		v.skipSpace()
+		alternatives.AppendValue(v.importConcatenation())
+	}
+	return ExpressionClass().FromAlternatives(alternatives)
+}
+
+// This private class method imports a quoted string.  ABNF strings are case
+// insensitive unless they are prefixed with %s (RFC 7405).
+func (v *importer_) importCharacters(isSensitive bool) AssertionLike {
+	var start = v.position
+	v.position++
+	var text []rune
+	for !v.isNext('"') {
+		if v.position == len(v.runes) || v.runes[v.position] == '\n' {
+			panic(fmt.Sprintf("The string at %v is not terminated.", v.formatLocation()))
+		}
+		text = append(text, v.runes[v.position])
+		v.position++
+	}
+	v.position++
+	if len(text) == 0 {
+		v.position = start
+		panic(fmt.Sprintf("The empty string at %v cannot be imported.", v.formatLocation()))
+	}
+	if !isSensitive && !v.isCore && sts.ToLower(string(text)) != sts.ToUpper(string(text)) {
+		v.reportLoss(
+			v.spanOf(start, v.position),
+			fmt.Sprintf("The string \"%v\" is case insensitive but only this case is matched.",
+				string(text)),
+		)
+	}
+	var element = ElementClass().FromLiteral(quoteLiteral(string(text)))
+	element.SetSpan(v.spanOf(start, v.position))
+	return AssertionClass().FromElement(element)
+}
+
+// This private class method imports a sequence of comment lines as a single
+// comment statement.
+func (v *importer_) importComment() {
+	var lines []string
+	for v.isNext(';') {
+		var start = v.position + 1
+		v.skipLine()
+		var line = sts.TrimSpace(string(v.runes[start:v.position]))
+		lines = append(lines, "    "+sts.ReplaceAll(line, "<!", "< !"))
+		v.position++
+		for v.isNext(' ') || v.isNext('\t') {
+			v.position++
+		}
+	}
+	var comment = "!>\n" + sts.Join(lines, "\n") + "\n<!"
+	v.statements.AppendValue(StatementClass().FromComment(comment))
+}
+
+func (v *importer_) importConcatenation() AlternativeLike {
+	var factors = col.ListClass[FactorLike]().Empty()
+	factors.AppendValue(v.importRepetition())
+	for {
+		var position = v.position
+		if !v.skipSpace() || !v.isElement() {
+			v.position = position
+			break
+		}
+		factors.AppendValue(v.importRepetition())
+	}
+	return AlternativeClass().FromFactors(factors)
+}
+
+// This private class method adds the core rules that are used, but not
+// defined, by the imported grammar.
+func (v *importer_) importCoreRules() {
+	var core = &importer_{
+		definitions: col.CatalogClass[string, DefinitionLike]().Empty(),
+		diagnostics: col.ListClass[DiagnosticLike]().Empty(),
+		isCore:      true,
+		names:       col.CatalogClass[string, string]().Empty(),
+		statements:  col.ListClass[StatementLike]().Empty(),
+	}
+	core.importRules(importerClass.coreRules)
+	var hasCore = false
+	var pending = v.definitions.GetKeys().AsArray()
+	for len(pending) > 0 {
+		var key = pending[0]
+		pending = pending[1:]
+		for _, reference := range collectNames(v.definitions.GetValue(key)) {
+			if v.definitions.GetValue(reference) != nil {
+				continue
+			}
+			var definition = core.definitions.GetValue(reference)
+			if definition == nil {
+				panic(fmt.Sprintf("The rule %v uses the undefined rule %v.",
+					v.names.GetValue(key), reference))
+			}
+			if !hasCore {
+				hasCore = true
+				v.statements.AppendValue(StatementClass().FromComment(`!>
+    CORE RULES
+    The following core rules are defined in appendix B of RFC 5234.
+<!`))
+			}
+			v.definitions.SetValue(reference, definition)
+			v.names.SetValue(reference, core.names.GetValue(reference))
+			v.statements.AppendValue(StatementClass().FromDefinition(definition))
+			pending = append(pending, reference)
+		}
+	}
+}
+
+func (v *importer_) importDigits() string {
+	var start = v.position
+	for v.position < len(v.runes) && uni.IsDigit(v.runes[v.position]) {
+		v.position++
+	}
+	return string(v.runes[start:v.position])
+}
+
+// This private class method imports an element and returns its assertion and
+// whether or not the element is optional.
+func (v *importer_) importElement() (AssertionLike, bool) {
+	var start = v.position
+	switch {
+	case v.position < len(v.runes) && isAlpha(v.runes[v.position]):
+		var name = v.importRulename()
+		var element = ElementClass().FromName(sts.ToLower(name))
+		element.SetSpan(v.spanOf(start, v.position))
+		return AssertionClass().FromElement(element), false
+	case v.isNext('('), v.isNext('['):
+		var isOptional = v.isNext('[')
+		var closing = ')'
+		if isOptional {
+			closing = ']'
+		}
+		v.position++
+		v.skipSpace()
+		var expression = v.importAlternation()
+		v.skipSpace()
+		if !v.isNext(closing) {
+			panic(fmt.Sprintf("Expected %q at %v.", closing, v.formatLocation()))
+		}
+		v.position++
+		var alternatives = expression.GetAlternatives()
+		if alternatives.GetSize() == 1 {
+			var factors = alternatives.AsArray()[0].GetFactors()
+			var factor = factors.AsArray()[0]
+			if factors.GetSize() == 1 && factor.GetCardinality() == nil {
+				// Unnecessary parentheses are removed.
+				return factor.GetPredicate().GetAssertion(), isOptional
+			}
+		}
+		var precedence = PrecedenceClass().FromExpression(expression)
+		precedence.SetSpan(v.spanOf(start, v.position))
+		return AssertionClass().FromPrecedence(precedence), isOptional
+	case v.isNext('"'):
+		return v.importCharacters(false), false
+	case v.isNext('%'):
+		v.position++
+		switch {
+		case v.isNext('s') || v.isNext('S'):
+			v.position++
+			return v.importCharacters(true), false
+		case v.isNext('i') || v.isNext('I'):
+			v.position++
+			return v.importCharacters(false), false
+		default:
+			return v.importNumbers(start), false
+		}
+	case v.isNext('<'):
+		v.skipUntil('>')
+		v.position++
+		var prose = string(v.runes[start:v.position])
+		v.reportLoss(
+			v.spanOf(start, v.position),
+			fmt.Sprintf("The prose %v must be replaced manually.", prose),
+		)
+		var element = ElementClass().FromLiteral(quoteLiteral(prose))
+		element.SetSpan(v.spanOf(start, v.position))
+		return AssertionClass().FromElement(element), false
+	default:
+		panic(fmt.Sprintf("Expected an element at %v.", v.formatLocation()))
+	}
+}
+
+// This private class method imports a numeric value: a single character, a
+// sequence of characters or a range of characters.
+func (v *importer_) importNumbers(start int) AssertionLike {
+	var base int
+	switch {
+	case v.isNext('b') || v.isNext('B'):
+		base = 2
+	case v.isNext('d') || v.isNext('D'):
+		base = 10
+	case v.isNext('x') || v.isNext('X'):
+		base = 16
+	default:
+		panic(fmt.Sprintf("Expected \"b\", \"d\" or \"x\" at %v.", v.formatLocation()))
+	}
+	v.position++
+	var characters = []rune{v.importNumber(base)}
+	var isRange = v.isNext('-')
+	if isRange {
+		v.position++
+		characters = append(characters, v.importNumber(base))
+	} else {
+		for v.isNext('.') {
+			v.position++
+			characters = append(characters, v.importNumber(base))
+		}
+	}
+	var span = v.spanOf(start, v.position)
+	if isRange {
+		var assertion = v.importRange(characters[0], characters[1])
+		return assertion
+	}
+	if len(characters) == 1 && !uni.IsControl(characters[0]) {
+		var glyph = GlyphClass().FromCharacter("'" + string(characters[0]) + "'")
+		glyph.SetSpan(span)
+		return AssertionClass().FromGlyph(glyph)
+	}
+	var element = ElementClass().FromLiteral(quoteLiteral(string(characters)))
+	element.SetSpan(span)
+	return AssertionClass().FromElement(element)
+}
+
+func (v *importer_) importNumber(base int) rune {
+	var start = v.position
+	for v.position < len(v.runes) && sts.ContainsRune("0123456789abcdefABCDEF", v.runes[v.position]) {
+		v.position++
+	}
+	var number, err = stc.ParseInt(string(v.runes[start:v.position]), base, 32)
+	if err != nil || number > uni.MaxRune {
+		v.position = start
+		panic(fmt.Sprintf("Expected a character value at %v.", v.formatLocation()))
+	}
+	return rune(number)
+}
+
+// This private class method returns an assertion matching the specified range
+// of characters.  Control characters cannot appear within a CDSN glyph so they
+// are matched separately.
+func (v *importer_) importRange(first, last rune) AssertionLike {
+	if first > last {
+		panic(fmt.Sprintf("The range of characters before %v is empty.", v.formatLocation()))
+	}
+	var assertions []AssertionLike
+	var isControl = first == 0 && last >= 0x9f
+	if isControl {
+		// The range contains every control character.
+		var element = ElementClass().FromIntrinsic("CONTROL")
+		assertions = append(assertions, AssertionClass().FromElement(element))
+	}
+	var start = rune(-1)
+	var addGlyph = func(end rune) {
+		if start < 0 {
+			return
+		}
+		var glyph GlyphLike
+		if start == end {
+			glyph = GlyphClass().FromCharacter("'" + string(start) + "'")
+		} else {
+			glyph = GlyphClass().FromRange("'"+string(start)+"'", "'"+string(end)+"'")
+		}
+		assertions = append(assertions, AssertionClass().FromGlyph(glyph))
+		start = -1
+	}
+	for character := first; character <= last; character++ {
+		if character > 0x9f {
+			// There are no more control characters.
+			if start < 0 {
+				start = character
+			}
+			break
+		}
+		if !uni.IsControl(character) {
+			if start < 0 {
+				start = character
+			}
+			continue
+		}
+		addGlyph(character - 1)
+		if !isControl {
+			var element = ElementClass().FromLiteral(quoteLiteral(string(character)))
+			assertions = append(assertions, AssertionClass().FromElement(element))
+		}
+	}
+	addGlyph(last)
+	if len(assertions) == 1 {
+		return assertions[0]
+	}
+	var alternatives = col.ListClass[AlternativeLike]().Empty()
+	for _, assertion := range assertions {
+		var factor = FactorClass().FromPredicate(PredicateClass().FromAssertion(assertion, false))
+		var factors = col.ListClass[FactorLike]().FromArray([]FactorLike{factor})
+		alternatives.AppendValue(AlternativeClass().FromFactors(factors))
+	}
+	var expression = ExpressionClass().FromAlternatives(alternatives)
+	return AssertionClass().FromPrecedence(PrecedenceClass().FromExpression(expression))
+}
+
+// This private class method imports a repetition: an element preceded by an
+// optional repeat count of the form N, N*M, N*, *M or *.
+func (v *importer_) importRepetition() FactorLike {
+	var start = v.position
+	var minimum = v.importDigits()
+	var maximum = minimum
+	if v.isNext('*') {
+		v.position++
+		maximum = v.importDigits()
+		if len(minimum) == 0 {
+			minimum = "0"
+		}
+	}
+	var assertion, isOptional = v.importElement()
+	var factor = FactorClass().FromPredicate(PredicateClass().FromAssertion(assertion, false))
+	factor.SetSpan(v.spanOf(start, v.position))
+	switch {
+	case len(minimum) == 0 && isOptional:
+		minimum, maximum = "0", "1"
+	case len(minimum) == 0:
+		return factor
+	case isOptional:
+		// Each instance of an optional element may be empty.
+		minimum = "0"
+	}
+	minimum = normalizeNumber(minimum)
+	maximum = normalizeNumber(maximum)
+	if minimum == "1" && maximum == "1" {
+		return factor
+	}
+	var constraint = ConstraintClass().FromRange(minimum, maximum)
+	factor.SetCardinality(CardinalityClass().FromConstraint(constraint))
+	return factor
+}
+
+// This private class method imports a rule definition.  Incremental
+// alternatives (=/) are appended to the existing definition of the rule.
+func (v *importer_) importRule() {
+	var start = v.position
+	v.notes = col.CatalogClass[int, string]().Empty()
+	v.symbol = v.importRulename()
+	var key = sts.ToLower(v.symbol)
+	v.skipSpace()
+	if !v.isNext('=') {
+		panic(fmt.Sprintf("Expected \"=\" at %v.", v.formatLocation()))
+	}
+	v.position++
+	var isIncremental = v.isNext('/')
+	if isIncremental {
+		v.position++
+	}
+	v.skipSpace()
+	var expression = v.importAlternation()
+	v.skipSpace()
+	if v.position < len(v.runes) && !v.isNext('\n') {
+		panic(fmt.Sprintf("Expected the end of the rule at %v.", v.formatLocation()))
+	}
+	var span = v.spanOf(start, v.position)
+	expression.SetMultilined(
+		expression.GetAlternatives().GetSize() > 1 &&
+			sts.ContainsRune(string(v.runes[start:v.position]), '\n'),
+	)
+	var notes = v.notes.GetValues(v.notes.GetKeys()).AsArray()
+	if len(notes) > 0 {
+		var alternatives = expression.GetAlternatives().AsArray()
+		alternatives[len(alternatives)-1].SetNote("! " + sts.Join(notes, " "))
+	}
+	var definition = v.definitions.GetValue(key)
+	switch {
+	case definition != nil && isIncremental:
+		v.reportLoss(span, fmt.Sprintf("The incremental alternatives for %v were appended to its definition.", v.symbol))
+		var alternatives = col.ListClass[AlternativeLike]().FromSequence(
+			definition.GetExpression().GetAlternatives(),
+		)
+		alternatives.AppendValues(expression.GetAlternatives())
+		definition.GetExpression().SetAlternatives(alternatives)
+		definition.GetExpression().SetMultilined(
+			definition.GetExpression().IsMultilined() || expression.IsMultilined(),
+		)
+	case definition != nil:
+		panic(fmt.Sprintf("The rule %v is defined more than once.", v.symbol))
+	case isIncremental:
+		panic(fmt.Sprintf("The incremental alternatives for %v have no definition.", v.symbol))
+	default:
+		definition = DefinitionClass().FromSymbolAndExpression("$"+key, expression)
+		definition.SetSpan(span)
+		v.definitions.SetValue(key, definition)
+		v.names.SetValue(key, v.symbol)
+		v.statements.AppendValue(StatementClass().FromDefinition(definition))
+	}
+}
+
+func (v *importer_) importRulename() string {
+	var start = v.position
+	for v.position < len(v.runes) {
+		var character = v.runes[v.position]
+		if !isAlpha(character) && !uni.IsDigit(character) && character != '-' {
+			break
+		}
+		v.position++
+	}
+	return string(v.runes[start:v.position])
+}
+
+// This private class method imports the rules and comments in the specified
+// ABNF source.
+func (v *importer_) importRules(source string) {
+	v.runes = []rune(sts.ReplaceAll(source, "\r\n", "\n"))
+	v.position = 0
+	var line, position, offset = 1, 1, 0
+	v.locations = make([]LocationLike, len(v.runes)+1)
+	for index, character := range v.runes {
+		v.locations[index] = LocationClass().FromPosition(line, position, offset)
+		offset += len(string(character))
+		position++
+		if character == '\n' {
+			line++
+			position = 1
+		}
+	}
+	v.locations[len(v.runes)] = LocationClass().FromPosition(line, position, offset)
+	for v.position < len(v.runes) {
+		var character = v.runes[v.position]
+		switch {
+		case character == ' ', character == '\t', character == '\n':
+			v.position++
+		case character == ';':
+			v.importComment()
+		case isAlpha(character):
+			v.importRule()
+		default:
+			panic(fmt.Sprintf("Expected a rule name at %v.", v.formatLocation()))
+		}
+	}
+}
+
+// This private class method determines whether or not an element begins at
+// the current position.
+func (v *importer_) isElement() bool {
+	if v.position == len(v.runes) {
+		return false
+	}
+	var character = v.runes[v.position]
+	return isAlpha(character) || uni.IsDigit(character) || sts.ContainsRune(`*(["%<`, character)
+}
+
+func (v *importer_) isNext(character rune) bool {
+	return v.position < len(v.runes) && v.runes[v.position] == character
+}
+
+// This private class method records that the specified construct cannot be
+// imported exactly.
+func (v *importer_) reportLoss(span SpanLike, message string) {
+	if v.isCore {
+		return
+	}
+	var diagnostic = DiagnosticClass().FromKind(
+		DiagnosticClass().GetLossy(),
+		DiagnosticClass().GetWarning(),
+		"$"+sts.ToLower(v.symbol), // Renamed once the rules are classified.
+		message,
+		span,
+	)
+	v.diagnostics.AppendValue(diagnostic)
+}
+
+func (v *importer_) skipLine() {
+	v.skipUntil('\n')
+}
+
+// This private class method skips any spaces and comments, including line
+// breaks that are followed by spaces, and returns whether or not anything was
+// skipped.  The comments are recorded as notes for the current rule.
+func (v *importer_) skipSpace() bool {
+	var start = v.position
+	for v.position < len(v.runes) {
+		var character = v.runes[v.position]
+		switch {
+		case character == ' ', character == '\t':
+			v.position++
+		case character == ';':
+			var comment = v.position
+			v.skipLine()
+			var note = sts.TrimSpace(string(v.runes[comment+1 : v.position]))
+			if len(note) > 0 && v.notes != nil {
+				v.notes.SetValue(comment, note)
+			}
+		case character == '\n' && v.position+1 < len(v.runes) &&
+			(v.runes[v.position+1] == ' ' || v.runes[v.position+1] == '\t'):
+			v.position++
+		default:
+			return v.position > start
+		}
+	}
+	return v.position > start
+}
+
+func (v *importer_) skipUntil(character rune) {
+	for v.position < len(v.runes) && v.runes[v.position] != character {
+		v.position++
+	}
+}
+
+func (v *importer_) spanOf(start, end int) SpanLike {
+	return SpanClass().FromLocations(v.locations[start], v.locations[end])
+}
+
+// PRIVATE TYPES
+
+// This private type is a visitor that collects the names used within a
+// definition.
+type nameCollector_ struct {
+	VisitorLike
+	names []string
+}
+
+func (v *nameCollector_) PreElement(element ElementLike, walker WalkerLike) bool {
+	var name = element.GetName()
+	if len(name) > 0 {
+		v.names = append(v.names, name)
+	}
+	return false
+}
+
+// This private type is a visitor that renames the names within a definition.
+type nameRenamer_ struct {
+	VisitorLike
+	names col.CatalogLike[string, string]
+}
+
+func (v *nameRenamer_) PreElement(element ElementLike, walker WalkerLike) bool {
+	var name = v.names.GetValue(element.GetName())
+	if len(name) > 0 {
+		element.SetName(name)
+	}
+	return false
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the names used within the specified
+// definition.
+func collectNames(definition DefinitionLike) []string {
+	var collector = &nameCollector_{
+		VisitorLike: VisitorClass().Default(),
+	}
+	WalkerClass().FromVisitor(collector).WalkDefinition(definition)
+	return collector.names
+}
+
+// This private function determines whether or not the specified character is
+// an ASCII letter.
+func isAlpha(character rune) bool {
+	return character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z'
+}
+
+// This private function returns the CDSN name for the specified ABNF name.
+// Token names are upper case and rule names are lower case.  A token name that
+// would be mistaken for an intrinsic is prefixed with "ABNF_".
+func makeName(name string, isRule bool) string {
+	var parts = sts.FieldsFunc(name, func(character rune) bool {
+		return character == '-'
+	})
+	name = sts.Join(parts, "_")
+	if isRule {
+		return sts.ToLower(name)
+	}
+	name = sts.ToUpper(name)
+	if len(ScannerClass().MatchIntrinsic(name)) > 0 &&
+		ScannerClass().MatchIntrinsic(name)[0] == name {
+		name = "ABNF_" + name
+	}
+	return name
+}
+
+// This private function returns the specified number without leading zeros.
+func normalizeNumber(number string) string {
+	if len(number) == 0 {
+		return number
+	}
+	var value, _ = stc.Atoi(number)
+	return stc.Itoa(value)
+}
+
+// This private function returns a CDSN literal for the specified text.
+func quoteLiteral(text string) string {
+	var builder sts.Builder
+	builder.WriteString(`"`)
+	for _, character := range text {
+		switch {
+		case character == '"', character == '\\':
+			builder.WriteString(`\` + string(character))
+		case character == '\n':
+			builder.WriteString(`\n`)
+		case character == '\r':
+			builder.WriteString(`\r`)
+		case character == '\t':
+			builder.WriteString(`\t`)
+		case !uni.IsControl(character):
+			builder.WriteRune(character)
+		default:
+			builder.WriteString(fmt.Sprintf(`\x%02x`, character))
+		}
+	}
+	builder.WriteString(`"`)
+	return builder.String()
+}
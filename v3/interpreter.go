@@ -0,0 +1,540 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+	uni "unicode"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type interpreterClass_ struct {
+	defaultSymbol string
+}
+
+// Private Class Namespace Reference
+
+var interpreterClass = &interpreterClass_{
+	defaultSymbol: "$document",
+}
+
+// Public Class Namespace Access
+
+func InterpreterClass() InterpreterClassLike {
+	return interpreterClass
+}
+
+// Public Class Constructors
+
+func (c *interpreterClass_) FromDocument(document DocumentLike) InterpreterLike {
+	return c.FromDocumentAndSymbol(document, c.defaultSymbol)
+}
+
+func (c *interpreterClass_) FromDocumentAndSymbol(
+	document DocumentLike,
+	symbol string,
+) InterpreterLike {
+	var interpreter = &interpreter_{
+		definitions: make(map[string]DefinitionLike),
+		grammar:     make(map[string]string),
+		symbol:      symbol,
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil {
+			continue
+		}
+		var symbol = definition.GetSymbol()
+		var formatted = FormatterClass().Default().FormatDefinition(definition)
+		interpreter.definitions[symbol[1:]] = definition
+		interpreter.grammar[symbol] = sts.TrimSpace(formatted[len(symbol)+1:])
+	}
+	var name = sts.TrimPrefix(symbol, "$")
+	if _, ok := interpreter.definitions[name]; !ok || !isRuleName(name) {
+		panic(fmt.Sprintf("The start symbol must name a rule definition: %v", symbol))
+	}
+	interpreter.terminals = collectTerminals(document)
+	return interpreter
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type interpreter_ struct {
+	definitions map[string]DefinitionLike // The definitions keyed by name.
+	expected    []string                  // The terminals expected at the failure.
+	failure     int                       // The farthest position of a failure.
+	grammar     map[string]string         // The expressions keyed by symbol.
+	indices     []int                     // The rune index at each byte offset.
+	locations   []LocationLike            // The location of each rune.
+	longest     map[int]terminal_         // The longest terminals by position.
+	matcher     *matcher_
+	memos       map[memo_]*result_
+	rules       []string // The stack of rules currently being parsed.
+	runes       []rune
+	source      string
+	stack       []string // The stack of rules at the failure.
+	symbol      string
+	terminals   []terminal_ // The terminals that may appear within rules.
+}
+
+// Public Interface
+
+func (v *interpreter_) GetSymbol() string {
+	return v.symbol
+}
+
+func (v *interpreter_) ParseSource(source string) (NodeLike, error) {
+	v.initialize(source)
+	var node, position, ok = v.parseRule(sts.TrimPrefix(v.symbol, "$"), 0)
+	if ok {
+		position = v.skipWhitespace(position)
+		if position == len(v.runes) {
+			return node, nil
+		}
+		v.expect(position, "EOF")
+	}
+	return nil, v.unexpectedInput()
+}
+
+// Private Interface
+
+// This private class method records that the specified terminal was expected
+// at the specified position.  Only the terminals expected at the farthest
+// position reached are kept.
+func (v *interpreter_) expect(position int, expected string) {
+	if position < v.failure {
+		return
+	}
+	if position > v.failure {
+		v.failure = position
+		v.expected = nil
+		v.stack = append([]string{}, v.rules...)
+	}
+	for _, existing := range v.expected {
+		if existing == expected {
+			return
+		}
+	}
+	v.expected = append(v.expected, expected)
+}
+
+// This private class method resets the state of the interpreter for parsing the
+// specified source text.
+func (v *interpreter_) initialize(source string) {
+	v.source = source
+	v.runes = []rune(source)
+	v.matcher = matcherClass.fromRunes(v.runes, v.definitions)
+	v.memos = make(map[memo_]*result_)
+	v.longest = make(map[int]terminal_)
+	v.expected = nil
+	v.failure = -1
+	v.rules = nil
+	v.stack = nil
+
+	// Calculate the location of each rune in the source text and the index of
+	// the rune at each byte offset.
+	var line, position, offset = 1, 1, 0
+	v.locations = make([]LocationLike, len(v.runes)+1)
+	v.indices = make([]int, len(source)+1)
+	for index, character := range v.runes {
+		v.locations[index] = LocationClass().FromPosition(line, position, offset)
+		v.indices[offset] = index
+		offset += len(string(character))
+		position++
+		if character == '\n' {
+			line++
+			position = 1
+		}
+	}
+	v.locations[len(v.runes)] = LocationClass().FromPosition(line, position, offset)
+	v.indices[offset] = len(v.runes)
+}
+
+// This private class method returns the longest terminal that matches the
+// source text at the specified position.  The length of the terminal is zero
+// if no terminal matches.  This emulates a scanner that always chooses the
+// longest possible token.
+func (v *interpreter_) longestTerminal(position int) terminal_ {
+	var longest, ok = v.longest[position]
+	if ok {
+		return longest
+	}
+	for _, terminal := range v.terminals {
+		var end, ok = v.matchTerminal(terminal.assertion, position)
+		if ok && end-position > longest.length {
+			longest = terminal
+			longest.length = end - position
+		}
+	}
+	v.longest[position] = longest
+	return longest
+}
+
+// This private class method attempts to match the specified terminal assertion
+// at the specified position and returns the position just past the match.
+func (v *interpreter_) matchTerminal(
+	assertion AssertionLike,
+	position int,
+) (end int, ok bool) {
+	ok = v.matcher.matchAssertion(assertion, position, func(next int) bool {
+		end = next
+		return end > position
+	})
+	return end, ok
+}
+
+func (v *interpreter_) parseAlternative(
+	alternative AlternativeLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var children []NodeLike
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		var factor = iterator.GetNext()
+		var nodes, next, ok = v.parseFactor(factor, position)
+		if !ok {
+			return nil, position, false
+		}
+		children = append(children, nodes...)
+		position = next
+	}
+	return children, position, true
+}
+
+func (v *interpreter_) parseAssertion(
+	assertion AssertionLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case precedence != nil:
+		return v.parseExpression(precedence.GetExpression(), position)
+	case element != nil && element.GetIntrinsic() == "EOF":
+		return v.parseEOF(position)
+	case element != nil && isRuleName(element.GetName()):
+		var node, next, ok = v.parseRule(element.GetName(), position)
+		if !ok {
+			return nil, position, false
+		}
+		return []NodeLike{node}, next, true
+	default:
+		return v.parseTerminal(assertion, position)
+	}
+}
+
+func (v *interpreter_) parseEOF(position int) ([]NodeLike, int, bool) {
+	var end = v.skipWhitespace(position)
+	if end < len(v.runes) {
+		v.expect(v.skipSpaces(position), "EOF")
+		return nil, position, false
+	}
+	var span = SpanClass().FromLocations(v.locations[end], v.locations[end])
+	var node = NodeClass().FromToken("EOF", "", span)
+	return []NodeLike{node}, end, true
+}
+
+func (v *interpreter_) parseExpression(
+	expression ExpressionLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		var children, next, ok = v.parseAlternative(alternative, position)
+		if ok {
+			return children, next, true
+		}
+	}
+	return nil, position, false
+}
+
+func (v *interpreter_) parseFactor(
+	factor FactorLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var children []NodeLike
+	var predicate = factor.GetPredicate()
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	var count = 0
+	var next = position
+	for maximum < 0 || count < maximum {
+		var nodes, end, ok = v.parsePredicate(predicate, next)
+		if !ok {
+			break
+		}
+		children = append(children, nodes...)
+		count++
+		if end == next && count >= minimum {
+			// Don't loop forever matching nothing.
+			break
+		}
+		next = end
+	}
+	if count < minimum {
+		return nil, position, false
+	}
+	return children, next, true
+}
+
+// This private class method parses an inverted predicate which matches any
+// single terminal that does not match the predicate.
+func (v *interpreter_) parseInversion(
+	assertion AssertionLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var start = v.skipSpaces(position)
+	var longest = v.longestTerminal(start)
+	if longest.length == 0 {
+		v.expect(start, "~"+describeAssertion(assertion))
+		return nil, position, false
+	}
+	var end = start + longest.length
+	var next, ok = v.matchTerminal(assertion, start)
+	if ok && next == end {
+		v.expect(start, "~"+describeAssertion(assertion))
+		return nil, position, false
+	}
+	var node = NodeClass().FromToken(longest.name, v.textOf(start, end), v.spanOf(start, end))
+	return []NodeLike{node}, end, true
+}
+
+func (v *interpreter_) parsePredicate(
+	predicate PredicateLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var assertion = predicate.GetAssertion()
+	if predicate.IsInverted() {
+		return v.parseInversion(assertion, position)
+	}
+	return v.parseAssertion(assertion, position)
+}
+
+// This private class method parses the rule with the specified name at the
+// specified position.  The results are memoized, and a rule that is reentered
+// at the same position (left recursion) fails.
+func (v *interpreter_) parseRule(name string, position int) (NodeLike, int, bool) {
+	var key = memo_{name: name, position: position}
+	var result, ok = v.memos[key]
+	if ok {
+		return result.node, result.end, result.ok
+	}
+	result = &result_{end: position}
+	v.memos[key] = result
+
+	v.rules = append(v.rules, "$"+name)
+	var definition = v.definitions[name]
+	var children, end, matched = v.parseExpression(definition.GetExpression(), position)
+	v.rules = v.rules[:len(v.rules)-1]
+	if !matched {
+		return nil, position, false
+	}
+
+	var start = position
+	var finish = position
+	if len(children) > 0 {
+		start = v.indexOf(children[0].GetSpan().GetStart())
+		finish = v.indexOf(children[len(children)-1].GetSpan().GetEnd())
+	}
+	var node = NodeClass().FromRule(
+		name,
+		v.textOf(start, finish),
+		col.ArrayClass[NodeLike]().FromArray(children),
+		v.spanOf(start, finish),
+	)
+	result.node = node
+	result.end = end
+	result.ok = true
+	return node, end, true
+}
+
+// This private class method parses a terminal at the specified position.  Any
+// spaces preceding the terminal are skipped unless the terminal itself begins
+// with a space.  The terminal only matches if no other terminal is longer.
+func (v *interpreter_) parseTerminal(
+	assertion AssertionLike,
+	position int,
+) ([]NodeLike, int, bool) {
+	var description = describeAssertion(assertion)
+	var start = position
+	var end, ok = v.matchTerminal(assertion, start)
+	if !ok || end-start < v.longestTerminal(start).length {
+		start = v.skipSpaces(position)
+		end, ok = v.matchTerminal(assertion, start)
+		if !ok || end-start < v.longestTerminal(start).length {
+			v.expect(start, description)
+			return nil, position, false
+		}
+	}
+	var node = NodeClass().FromToken(description, v.textOf(start, end), v.spanOf(start, end))
+	return []NodeLike{node}, end, true
+}
+
+func (v *interpreter_) indexOf(location LocationLike) int {
+	return v.indices[location.GetOffset()]
+}
+
+func (v *interpreter_) skipSpaces(position int) int {
+	for position < len(v.runes) && v.runes[position] == ' ' {
+		position++
+	}
+	return position
+}
+
+func (v *interpreter_) skipWhitespace(position int) int {
+	for position < len(v.runes) && (v.runes[position] == ' ' || v.runes[position] == '\n') {
+		position++
+	}
+	return position
+}
+
+func (v *interpreter_) spanOf(start, end int) SpanLike {
+	return SpanClass().FromLocations(v.locations[start], v.locations[end])
+}
+
+func (v *interpreter_) textOf(start, end int) string {
+	return string(v.runes[start:end])
+}
+
+// This private class method creates a parse error describing the farthest
+// position in the source text that could not be parsed.
+func (v *interpreter_) unexpectedInput() *parseError_ {
+	var position = v.failure
+	var tokenType = TokenClass().GetEOF()
+	var end = position
+	if position < len(v.runes) {
+		var longest = v.longestTerminal(position)
+		tokenType = longest.name
+		end = position + longest.length
+		if longest.length == 0 {
+			tokenType = TokenClass().GetError()
+			end = position + 1
+		}
+	}
+	var token = TokenClass().FromSpan(
+		v.spanOf(position, end),
+		tokenType,
+		v.textOf(position, end),
+	)
+	var rules = v.stack
+	if len(rules) > 2 {
+		rules = rules[len(rules)-2:]
+	}
+	return parseErrorClass.fromUnexpectedInput(
+		v.source,
+		token,
+		sts.Join(v.expected, " | "),
+		v.grammar,
+		rules...,
+	)
+}
+
+// PRIVATE TYPES
+
+// This private type identifies the parsing of a rule at a position.
+type memo_ struct {
+	name     string
+	position int
+}
+
+// This private type records the result of parsing a rule at a position.
+type result_ struct {
+	end  int
+	node NodeLike
+	ok   bool
+}
+
+// This private type records a terminal that may appear within a rule.
+type terminal_ struct {
+	assertion AssertionLike
+	length    int
+	name      string
+}
+
+// This private type is a visitor that collects the terminals that appear within
+// the rule definitions of a document.
+type terminalCollector_ struct {
+	VisitorLike
+	terminals []terminal_
+}
+
+func (v *terminalCollector_) PreAssertion(assertion AssertionLike, walker WalkerLike) bool {
+	var element = assertion.GetElement()
+	switch {
+	case assertion.GetPrecedence() != nil:
+		return true
+	case element != nil && element.GetIntrinsic() == "EOF":
+	case element != nil && isRuleName(element.GetName()):
+	default:
+		var description = describeAssertion(assertion)
+		for _, terminal := range v.terminals {
+			if terminal.name == description {
+				return false
+			}
+		}
+		var terminal = terminal_{
+			assertion: assertion,
+			name:      description,
+		}
+		v.terminals = append(v.terminals, terminal)
+	}
+	return false
+}
+
+func (v *terminalCollector_) PreDefinition(definition DefinitionLike, walker WalkerLike) bool {
+	return isRuleName(definition.GetSymbol()[1:])
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the terminals (token names, literals, glyphs
+// and intrinsics) that appear within the rule definitions of the specified
+// document, in the order that they first appear.
+func collectTerminals(document DocumentLike) []terminal_ {
+	var collector = &terminalCollector_{
+		VisitorLike: VisitorClass().Default(),
+	}
+	WalkerClass().FromVisitor(collector).WalkDocument(document)
+	return collector.terminals
+}
+
+// This private function returns a description of the specified terminal
+// assertion as it would appear in a CDSN document.
+func describeAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	switch {
+	case element != nil:
+		return element.GetIntrinsic() + element.GetName() + element.GetLiteral()
+	case glyph != nil && len(glyph.GetLast()) > 0:
+		return glyph.GetFirst() + ".." + glyph.GetLast()
+	case glyph != nil:
+		return glyph.GetFirst()
+	default:
+		var formatter = FormatterClass().Default().(*formatter_)
+		formatter.formatPrecedence(assertion.GetPrecedence())
+		return formatter.getResult()
+	}
+}
+
+// This private function determines whether or not the specified name is the
+// name of a rule rather than a token.
+func isRuleName(name string) bool {
+	return len(name) > 0 && uni.IsLower([]rune(name)[0])
+}
@@ -0,0 +1,78 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type locationClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var locationClass = &locationClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func LocationClass() LocationClassLike {
+	return locationClass
+}
+
+// Public Class Constructors
+
+func (c *locationClass_) FromPosition(
+	line int,
+	position int,
+	offset int,
+) LocationLike {
+	var location = &location_{
+		line:     line,
+		offset:   offset,
+		position: position,
+	}
+	return location
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type location_ struct {
+	line     int // The line number in the document starting at one.
+	offset   int // The zero based byte offset into the document.
+	position int // The rune position in the line starting at one.
+}
+
+// Public Interface
+
+func (v *location_) GetLine() int {
+	return v.line
+}
+
+func (v *location_) GetOffset() int {
+	return v.offset
+}
+
+func (v *location_) GetPosition() int {
+	return v.position
+}
+
+func (v *location_) String() string {
+	return fmt.Sprintf("%v:%v", v.line, v.position)
+}
@@ -0,0 +1,349 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	stc "strconv"
+	uni "unicode"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type matcherClass_ struct {
+	maximumDepth int
+}
+
+// Private Class Namespace Reference
+
+var matcherClass = &matcherClass_{
+	maximumDepth: 64,
+}
+
+// Private Class Constructors
+
+// This private class constructor creates a new matcher that matches the
+// specified runes against token definitions at the character level.  Token
+// definitions are matched like regular expressions: repetitions are greedy,
+// except for repetitions of the ANY intrinsic which are lazy, and the matcher
+// backtracks until the rest of the token definition can be matched.
+func (c *matcherClass_) fromRunes(
+	runes []rune,
+	definitions map[string]DefinitionLike,
+) *matcher_ {
+	var matcher = &matcher_{
+		definitions: definitions,
+		runes:       runes,
+	}
+	return matcher
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type matcher_ struct {
+	definitions map[string]DefinitionLike // The definitions keyed by name.
+	depth       int                       // The nesting depth of token names.
+	runes       []rune
+}
+
+// Private Interface
+
+// This private class method returns the end position of the first match of the
+// specified token definition starting at the specified position.
+func (v *matcher_) matchToken(name string, position int) (int, bool) {
+	var end int
+	var ok = v.matchName(name, position, func(next int) bool {
+		end = next
+		return true
+	})
+	return end, ok
+}
+
+// This private class method determines whether or not the specified assertion
+// matches exactly the specified rune.
+func (v *matcher_) matchesRune(assertion AssertionLike, character rune) bool {
+	var single = matcherClass.fromRunes([]rune{character}, v.definitions)
+	single.depth = v.depth
+	return single.matchAssertion(assertion, 0, func(next int) bool {
+		return next == 1
+	})
+}
+
+func (v *matcher_) matchAlternative(
+	alternative AlternativeLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var factors = alternative.GetFactors().AsArray()
+	return v.matchFactors(factors, position, next)
+}
+
+func (v *matcher_) matchAssertion(
+	assertion AssertionLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.matchElement(element, position, next)
+	case glyph != nil:
+		return v.matchGlyph(glyph, position, next)
+	case precedence != nil:
+		return v.matchExpression(precedence.GetExpression(), position, next)
+	default:
+		panic("Attempted to match an empty assertion.")
+	}
+}
+
+func (v *matcher_) matchElement(
+	element ElementLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var intrinsic = element.GetIntrinsic()
+	var name = element.GetName()
+	var literal = element.GetLiteral()
+	switch {
+	case len(intrinsic) > 0:
+		return v.matchIntrinsic(intrinsic, position, next)
+	case len(name) > 0:
+		return v.matchName(name, position, next)
+	case len(literal) > 0:
+		return v.matchLiteral(literal, position, next)
+	default:
+		panic("Attempted to match an empty element.")
+	}
+}
+
+// This private class method matches an escape sequence of the form \x,
+// \xHH, \uHHHH or \UHHHHHHHH.
+func (v *matcher_) matchEscape(position int, next func(int) bool) bool {
+	var runes = v.runes
+	if position+1 >= len(runes) || runes[position] != '\\' {
+		return false
+	}
+	var digits int
+	switch runes[position+1] {
+	case 'a', 'b', 'f', 'n', 'r', 't', 'v', '\'', '"', '\\':
+		return next(position + 2)
+	case 'x':
+		digits = 2
+	case 'u':
+		digits = 4
+	case 'U':
+		digits = 8
+	default:
+		return false
+	}
+	var end = position + 2 + digits
+	if end > len(runes) {
+		return false
+	}
+	for _, digit := range runes[position+2 : end] {
+		if !(digit >= '0' && digit <= '9' || digit >= 'a' && digit <= 'f') {
+			return false
+		}
+	}
+	return next(end)
+}
+
+func (v *matcher_) matchExpression(
+	expression ExpressionLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		if v.matchAlternative(alternative, position, next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *matcher_) matchFactor(
+	factor FactorLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var predicate = factor.GetPredicate()
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	var element = predicate.GetAssertion().GetElement()
+	var isLazy = !predicate.IsInverted() && element != nil &&
+		element.GetIntrinsic() == "ANY"
+	var repeat func(count int, position int) bool
+	repeat = func(count int, position int) bool {
+		var more = func(next int) bool {
+			if next == position && count >= minimum {
+				// Don't loop forever matching nothing.
+				return false
+			}
+			return repeat(count+1, next)
+		}
+		if isLazy {
+			if count >= minimum && next(position) {
+				return true
+			}
+			return (maximum < 0 || count < maximum) &&
+				v.matchPredicate(predicate, position, more)
+		}
+		if (maximum < 0 || count < maximum) &&
+			v.matchPredicate(predicate, position, more) {
+			return true
+		}
+		return count >= minimum && next(position)
+	}
+	return repeat(0, position)
+}
+
+func (v *matcher_) matchFactors(
+	factors []FactorLike,
+	position int,
+	next func(int) bool,
+) bool {
+	if len(factors) == 0 {
+		return next(position)
+	}
+	return v.matchFactor(factors[0], position, func(position int) bool {
+		return v.matchFactors(factors[1:], position, next)
+	})
+}
+
+func (v *matcher_) matchGlyph(
+	glyph GlyphLike,
+	position int,
+	next func(int) bool,
+) bool {
+	if position >= len(v.runes) {
+		return false
+	}
+	var character = v.runes[position]
+	var first = unquoteCharacter(glyph.GetFirst())
+	var last = first
+	if len(glyph.GetLast()) > 0 {
+		last = unquoteCharacter(glyph.GetLast())
+	}
+	return character >= first && character <= last && next(position+1)
+}
+
+func (v *matcher_) matchIntrinsic(
+	intrinsic string,
+	position int,
+	next func(int) bool,
+) bool {
+	if intrinsic == "EOF" {
+		return position == len(v.runes) && next(position)
+	}
+	if intrinsic == "ESCAPE" {
+		return v.matchEscape(position, next)
+	}
+	if position >= len(v.runes) {
+		return false
+	}
+	var character = v.runes[position]
+	var matches bool
+	switch intrinsic {
+	case "ANY":
+		matches = true
+	case "LOWER":
+		matches = uni.IsLower(character)
+	case "UPPER":
+		matches = uni.IsUpper(character)
+	case "DIGIT":
+		matches = uni.IsDigit(character)
+	case "CONTROL":
+		matches = uni.IsControl(character)
+	case "EOL":
+		matches = character == '\n'
+	default:
+		panic(fmt.Sprintf("Attempted to match an unknown intrinsic: %v", intrinsic))
+	}
+	return matches && next(position+1)
+}
+
+func (v *matcher_) matchLiteral(
+	literal string,
+	position int,
+	next func(int) bool,
+) bool {
+	var text = []rune(unquoteLiteral(literal))
+	if position+len(text) > len(v.runes) {
+		return false
+	}
+	for index, character := range text {
+		if v.runes[position+index] != character {
+			return false
+		}
+	}
+	return next(position + len(text))
+}
+
+func (v *matcher_) matchName(
+	name string,
+	position int,
+	next func(int) bool,
+) bool {
+	var definition, ok = v.definitions[name]
+	if !ok {
+		panic(fmt.Sprintf("The grammar is missing a definition for name: %v", name))
+	}
+	if !uni.IsUpper([]rune(name)[0]) {
+		panic(fmt.Sprintf("A token cannot be matched against a rule name: %v", name))
+	}
+	if v.depth > matcherClass.maximumDepth {
+		panic(fmt.Sprintf("The token definition for $%v is recursive.", name))
+	}
+	v.depth++
+	var matched = v.matchExpression(definition.GetExpression(), position, next)
+	v.depth--
+	return matched
+}
+
+func (v *matcher_) matchPredicate(
+	predicate PredicateLike,
+	position int,
+	next func(int) bool,
+) bool {
+	var assertion = predicate.GetAssertion()
+	if predicate.IsInverted() {
+		if position >= len(v.runes) || v.matchesRune(assertion, v.runes[position]) {
+			return false
+		}
+		return next(position + 1)
+	}
+	return v.matchAssertion(assertion, position, next)
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the minimum and maximum number of instances
+// allowed by the specified cardinality.  A maximum of -1 means unlimited.
+func getLimits(cardinality CardinalityLike) (minimum int, maximum int) {
+	if cardinality == nil {
+		return 1, 1
+	}
+	var constraint = cardinality.GetConstraint()
+	minimum, _ = stc.Atoi(constraint.GetFirst())
+	var last = constraint.GetLast()
+	if len(last) == 0 {
+		return minimum, -1
+	}
+	maximum, _ = stc.Atoi(last)
+	return minimum, maximum
+}
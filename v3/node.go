@@ -0,0 +1,104 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	col "github.com/craterdog/go-collection-framework/v3"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type nodeClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var nodeClass = &nodeClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func NodeClass() NodeClassLike {
+	return nodeClass
+}
+
+// Public Class Constructors
+
+func (c *nodeClass_) FromRule(
+	name string,
+	text string,
+	children col.Sequential[NodeLike],
+	span SpanLike,
+) NodeLike {
+	if children == nil {
+		panic("A rule node requires a sequence of children.")
+	}
+	var node = &node_{
+		children: children,
+		name:     name,
+		span:     span,
+		text:     text,
+	}
+	return node
+}
+
+func (c *nodeClass_) FromToken(
+	name string,
+	text string,
+	span SpanLike,
+) NodeLike {
+	var node = &node_{
+		children: col.ListClass[NodeLike]().Empty(),
+		isToken:  true,
+		name:     name,
+		span:     span,
+		text:     text,
+	}
+	return node
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type node_ struct {
+	children col.Sequential[NodeLike]
+	isToken  bool
+	name     string // The rule or token name, or the literal text of a terminal.
+	span     SpanLike
+	text     string // The source text that was matched by the node.
+}
+
+// Public Interface
+
+func (v *node_) GetChildren() col.Sequential[NodeLike] {
+	return v.children
+}
+
+func (v *node_) GetName() string {
+	return v.name
+}
+
+func (v *node_) GetSpan() SpanLike {
+	return v.span
+}
+
+func (v *node_) GetText() string {
+	return v.text
+}
+
+func (v *node_) IsToken() bool {
+	return v.isToken
+}
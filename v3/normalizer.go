@@ -0,0 +1,450 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	stc "strconv"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type normalizerClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var normalizerClass = &normalizerClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func NormalizerClass() NormalizerClassLike {
+	return normalizerClass
+}
+
+// Public Class Constructors
+
+func (c *normalizerClass_) Default() NormalizerLike {
+	var normalizer = &normalizer_{}
+	return normalizer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type normalizer_ struct {
+	base    string           // The name of the definition being normalized.
+	helpers []DefinitionLike // The helper rules created for the definition.
+	symbols map[string]bool  // The symbols that are already defined.
+}
+
+// Public Interface
+
+func (v *normalizer_) ExpandConstraints(document DocumentLike) {
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			v.expandExpression(definition.GetExpression())
+		}
+	}
+}
+
+func (v *normalizer_) ExtractPrecedences(document DocumentLike) {
+	v.normalizeRules(document, v.extractExpression)
+}
+
+func (v *normalizer_) FlattenPrecedences(document DocumentLike) {
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			v.flattenExpression(definition.GetExpression())
+		}
+	}
+}
+
+func (v *normalizer_) RemoveRepetitions(document DocumentLike) {
+	var analyzer = AnalyzerClass().FromDocument(document).(*analyzer_)
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil && isRuleName(definition.GetSymbol()[1:]) {
+			v.checkExpression(analyzer, definition.GetSymbol(), definition.GetExpression())
+		}
+	}
+	v.normalizeRules(document, func(expression ExpressionLike) {
+		v.expandExpression(expression)
+		v.removeExpression(expression)
+	})
+
+	// A token cannot be recursive so its repetitions are kept.
+	iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil && !isRuleName(definition.GetSymbol()[1:]) {
+			v.expandExpression(definition.GetExpression())
+		}
+	}
+}
+
+// Private Interface
+
+// This private class method makes sure that each unlimited repetition within
+// the specified rule expression can be removed.  The helper rule for the
+// repetition of something that can match nothing would be left recursive.
+func (v *normalizer_) checkExpression(
+	analyzer *analyzer_,
+	symbol string,
+	expression ExpressionLike,
+) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var factors = alternatives.GetNext().GetFactors().GetIterator()
+		for factors.HasNext() {
+			var factor = factors.GetNext()
+			var predicate = factor.GetPredicate()
+			var precedence = predicate.GetAssertion().GetPrecedence()
+			if precedence != nil {
+				v.checkExpression(analyzer, symbol, precedence.GetExpression())
+			}
+			var _, maximum = getLimits(factor.GetCardinality())
+			if maximum < 0 && analyzer.isNullableFactor(makeFactor(predicate, 1, 1)) {
+				panic(fmt.Sprintf("The repetition within %v cannot be removed since it can match nothing.", symbol))
+			}
+		}
+	}
+}
+
+// This private class method replaces each factor within the specified
+// expression whose constraint is not one of "?", "*" or "+" with explicit
+// repetitions of its predicate.  The predicate is repeated the minimum number
+// of times and is followed by nested optional repetitions up to the maximum,
+// or by a "+" repetition if the maximum is unlimited.
+func (v *normalizer_) expandExpression(expression ExpressionLike) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors []FactorLike
+		var iterator = alternative.GetFactors().GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			var precedence = factor.GetPredicate().GetAssertion().GetPrecedence()
+			if precedence != nil {
+				v.expandExpression(precedence.GetExpression())
+			}
+			factors = append(factors, v.expandFactor(factor)...)
+		}
+		if len(factors) == 0 {
+			// A factor that is repeated zero times is kept if it is the only one.
+			continue
+		}
+		alternative.SetFactors(col.ListClass[FactorLike]().FromArray(factors))
+	}
+}
+
+func (v *normalizer_) expandFactor(factor FactorLike) []FactorLike {
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	switch {
+	case minimum == 1 && maximum == 1:
+		return []FactorLike{factor}
+	case minimum == 0 && (maximum == 1 || maximum < 0):
+		return []FactorLike{factor}
+	case minimum == 1 && maximum < 0:
+		return []FactorLike{factor}
+	}
+	var predicate = factor.GetPredicate()
+	var factors []FactorLike
+	for i := 0; i < minimum; i++ {
+		factors = append(factors, makeFactor(copyPredicate(predicate), 1, 1))
+	}
+	if maximum < 0 {
+		// The last required repetition becomes a "+" repetition.
+		factors[minimum-1].SetCardinality(makeCardinality(1, -1))
+		return factors
+	}
+	if maximum > minimum {
+		// Build the nested optional repetitions from the inside out.
+		var optional = makeFactor(copyPredicate(predicate), 0, 1)
+		for i := minimum + 1; i < maximum; i++ {
+			var nested = []FactorLike{makeFactor(copyPredicate(predicate), 1, 1), optional}
+			optional = makeFactor(makePrecedence(nested), 0, 1)
+		}
+		factors = append(factors, optional)
+	}
+	return factors
+}
+
+// This private class method replaces each precedence within the specified
+// rule expression with the name of a new helper rule whose expression is that
+// of the precedence.  Inverted precedences are left since an inverted
+// assertion cannot contain a rule name.
+func (v *normalizer_) extractExpression(expression ExpressionLike) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var factors = alternatives.GetNext().GetFactors().GetIterator()
+		for factors.HasNext() {
+			var predicate = factors.GetNext().GetPredicate()
+			var precedence = predicate.GetAssertion().GetPrecedence()
+			if precedence == nil || predicate.IsInverted() {
+				continue
+			}
+			var name = v.makeName()
+			v.makeHelper(name, precedence.GetExpression())
+			predicate.SetAssertion(AssertionClass().FromElement(ElementClass().FromName(name)))
+		}
+	}
+}
+
+// This private class method replaces each precedence within the specified
+// expression that has a single alternative with its factors, or with the
+// single factor that it contains when that can be combined with the
+// cardinality or inversion of the precedence.
+func (v *normalizer_) flattenExpression(expression ExpressionLike) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors []FactorLike
+		var iterator = alternative.GetFactors().GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			var predicate = factor.GetPredicate()
+			var precedence = predicate.GetAssertion().GetPrecedence()
+			if precedence == nil {
+				factors = append(factors, factor)
+				continue
+			}
+			v.flattenExpression(precedence.GetExpression())
+			var inner = precedence.GetExpression().GetAlternatives().AsArray()
+			if len(inner) > 1 || len(inner[0].GetNote()) > 0 {
+				factors = append(factors, factor)
+				continue
+			}
+			var innerFactors = inner[0].GetFactors().AsArray()
+			var minimum, maximum = getLimits(factor.GetCardinality())
+			var isSingle = minimum == 1 && maximum == 1
+			if isSingle && !predicate.IsInverted() {
+				factors = append(factors, innerFactors...)
+				continue
+			}
+			if len(innerFactors) == 1 {
+				var innerFactor = innerFactors[0]
+				var innerMinimum, innerMaximum = getLimits(innerFactor.GetCardinality())
+				var innerPredicate = innerFactor.GetPredicate()
+				var isInnerSingle = innerMinimum == 1 && innerMaximum == 1
+				if isInnerSingle && !(predicate.IsInverted() && innerPredicate.IsInverted()) {
+					innerPredicate.SetInverted(predicate.IsInverted() || innerPredicate.IsInverted())
+					factors = append(factors, makeFactor(innerPredicate, minimum, maximum))
+					continue
+				}
+			}
+			factors = append(factors, factor)
+		}
+		alternative.SetFactors(col.ListClass[FactorLike]().FromArray(factors))
+	}
+}
+
+// This private class method creates a helper rule with the specified name and
+// expression.
+func (v *normalizer_) makeHelper(name string, expression ExpressionLike) {
+	var helper = DefinitionClass().FromSymbolAndExpression("$"+name, expression)
+	v.helpers = append(v.helpers, helper)
+}
+
+// This private class method returns a new name for a helper rule.  The name is
+// that of the definition being normalized followed by the first number that is
+// not already used.
+func (v *normalizer_) makeName() string {
+	var name string
+	for count := 1; ; count++ {
+		name = v.base + "_" + stc.Itoa(count)
+		if !v.symbols["$"+name] {
+			break
+		}
+	}
+	v.symbols["$"+name] = true
+	return name
+}
+
+// This private class method applies the specified transform to each rule
+// definition within the document and to each helper rule that it creates.  The
+// helper rules are inserted after the definition that uses them.  Token
+// definitions are left unchanged since they cannot contain rule names.
+func (v *normalizer_) normalizeRules(
+	document DocumentLike,
+	transform func(expression ExpressionLike),
+) {
+	var grammar = document.GetGrammar()
+	v.symbols = make(map[string]bool)
+	for symbol := range collectDefinitions(document.GetGrammar()) {
+		v.symbols[symbol] = true
+	}
+	var statements []StatementLike
+	var iterator = grammar.GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		statements = append(statements, statement)
+		var definition = statement.GetDefinition()
+		if definition == nil || !isRuleName(definition.GetSymbol()[1:]) {
+			continue
+		}
+		v.base = definition.GetSymbol()[1:]
+		v.helpers = nil
+		transform(definition.GetExpression())
+		for index := 0; index < len(v.helpers); index++ {
+			// Any helpers created while transforming a helper are appended.
+			var helper = v.helpers[index]
+			transform(helper.GetExpression())
+			statements = append(statements, StatementClass().FromDefinition(helper))
+		}
+	}
+	grammar.SetStatements(col.ListClass[StatementLike]().FromArray(statements))
+}
+
+// This private class method replaces each "*" or "+" repetition within the
+// specified rule expression with a right recursive helper rule so that "?" is
+// the only remaining cardinality.  A repetition of x is replaced with the
+// name of a helper rule "x h?", which is optional for a "*" repetition.
+func (v *normalizer_) removeExpression(expression ExpressionLike) {
+	var alternatives = expression.GetAlternatives().GetIterator()
+	for alternatives.HasNext() {
+		var alternative = alternatives.GetNext()
+		var factors []FactorLike
+		var iterator = alternative.GetFactors().GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			var predicate = factor.GetPredicate()
+			var precedence = predicate.GetAssertion().GetPrecedence()
+			if precedence != nil {
+				v.removeExpression(precedence.GetExpression())
+			}
+			var minimum, maximum = getLimits(factor.GetCardinality())
+			if maximum >= 0 {
+				factors = append(factors, factor)
+				continue
+			}
+			// The constraints have been expanded so the minimum is zero or one.
+			var name = v.makeName()
+			var recursion = makeFactor(makePredicate(name), 0, 1)
+			v.makeHelper(name, makeExpression(makeFactor(predicate, 1, 1), recursion))
+			factors = append(factors, makeFactor(makePredicate(name), minimum, 1))
+		}
+		alternative.SetFactors(col.ListClass[FactorLike]().FromArray(factors))
+	}
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns a deep copy of the specified expression
+// without any source spans.
+func copyExpression(expression ExpressionLike) ExpressionLike {
+	var alternatives []AlternativeLike
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		var factors []FactorLike
+		var factorIterator = alternative.GetFactors().GetIterator()
+		for factorIterator.HasNext() {
+			var factor = factorIterator.GetNext()
+			var minimum, maximum = getLimits(factor.GetCardinality())
+			factors = append(factors, makeFactor(copyPredicate(factor.GetPredicate()), minimum, maximum))
+		}
+		var copied = AlternativeClass().FromFactors(col.ListClass[FactorLike]().FromArray(factors))
+		if len(alternative.GetNote()) > 0 {
+			copied.SetNote(alternative.GetNote())
+		}
+		alternatives = append(alternatives, copied)
+	}
+	var copied = ExpressionClass().FromAlternatives(col.ListClass[AlternativeLike]().FromArray(alternatives))
+	copied.SetMultilined(expression.IsMultilined())
+	return copied
+}
+
+// This private function returns a deep copy of the specified predicate without
+// any source spans.
+func copyPredicate(predicate PredicateLike) PredicateLike {
+	var assertion = predicate.GetAssertion()
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	var copied AssertionLike
+	switch {
+	case element != nil && len(element.GetIntrinsic()) > 0:
+		copied = AssertionClass().FromElement(ElementClass().FromIntrinsic(element.GetIntrinsic()))
+	case element != nil && len(element.GetLiteral()) > 0:
+		copied = AssertionClass().FromElement(ElementClass().FromLiteral(element.GetLiteral()))
+	case element != nil:
+		copied = AssertionClass().FromElement(ElementClass().FromName(element.GetName()))
+	case glyph != nil && len(glyph.GetLast()) > 0:
+		copied = AssertionClass().FromGlyph(GlyphClass().FromRange(glyph.GetFirst(), glyph.GetLast()))
+	case glyph != nil:
+		copied = AssertionClass().FromGlyph(GlyphClass().FromCharacter(glyph.GetFirst()))
+	case precedence != nil:
+		var expression = copyExpression(precedence.GetExpression())
+		copied = AssertionClass().FromPrecedence(PrecedenceClass().FromExpression(expression))
+	default:
+		panic("Attempted to copy an empty assertion.")
+	}
+	return PredicateClass().FromAssertion(copied, predicate.IsInverted())
+}
+
+// This private function returns a cardinality with the specified limits, or
+// nil for exactly one.  A maximum of -1 means unlimited.
+func makeCardinality(minimum int, maximum int) CardinalityLike {
+	if minimum == 1 && maximum == 1 {
+		return nil
+	}
+	var last = ""
+	if maximum >= 0 {
+		last = stc.Itoa(maximum)
+	}
+	var constraint = ConstraintClass().FromRange(stc.Itoa(minimum), last)
+	return CardinalityClass().FromConstraint(constraint)
+}
+
+// This private function returns an expression containing a single alternative
+// made up of the specified factors.
+func makeExpression(factors ...FactorLike) ExpressionLike {
+	var alternative = AlternativeClass().FromFactors(col.ListClass[FactorLike]().FromArray(factors))
+	var alternatives = col.ListClass[AlternativeLike]().FromArray([]AlternativeLike{alternative})
+	return ExpressionClass().FromAlternatives(alternatives)
+}
+
+// This private function returns a factor for the specified predicate with the
+// specified limits.
+func makeFactor(predicate PredicateLike, minimum int, maximum int) FactorLike {
+	var factor = FactorClass().FromPredicate(predicate)
+	var cardinality = makeCardinality(minimum, maximum)
+	if cardinality != nil {
+		factor.SetCardinality(cardinality)
+	}
+	return factor
+}
+
+// This private function returns a predicate for the specified name.
+func makePredicate(name string) PredicateLike {
+	var assertion = AssertionClass().FromElement(ElementClass().FromName(name))
+	return PredicateClass().FromAssertion(assertion, false)
+}
+
+// This private function returns a predicate for a precedence containing a
+// single alternative made up of the specified factors.
+func makePrecedence(factors []FactorLike) PredicateLike {
+	var precedence = PrecedenceClass().FromExpression(makeExpression(factors...))
+	return PredicateClass().FromAssertion(AssertionClass().FromPrecedence(precedence), false)
+}
@@ -53,8 +53,35 @@ type AlternativeClassLike interface {
 type AlternativeLike interface {
 	GetFactors() col.Sequential[FactorLike]
 	GetNote() string
+	GetSpan() SpanLike
 	SetFactors(factors col.Sequential[FactorLike])
 	SetNote(note string)
+	SetSpan(span SpanLike)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all analyzer-class-like types.
+type AnalyzerClassLike interface {
+	FromDocument(document DocumentLike) AnalyzerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all analyzer-like types.  An analyzer-like type analyzes the rule
+// definitions in a document.  A rule is nullable if it can match without
+// consuming any tokens.  A rule is left recursive if it can be reentered before
+// consuming any tokens, the cycle of rules that leads back to it is nil if it
+// is not.  The first and follow sets of a rule contain the terminals that may
+// begin and follow it, an inverted terminal is prefixed with "~".  A conflict
+// is a choice within a rule that cannot be made by looking at the next token.
+// Terminals are compared by their descriptions, so a literal like "if" and a
+// token like IF that matches the same text are not reported as a conflict.
+type AnalyzerLike interface {
+	GetConflicts() col.Sequential[DiagnosticLike]
+	GetDiagnostics() col.Sequential[DiagnosticLike]
+	GetFirstSet(symbol string) col.Sequential[string]
+	GetFollowSet(symbol string) col.Sequential[string]
+	GetLeftRecursion(symbol string) col.Sequential[string]
+	IsNullable(symbol string) bool
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -71,9 +98,11 @@ type AssertionLike interface {
 	GetElement() ElementLike
 	GetGlyph() GlyphLike
 	GetPrecedence() PrecedenceLike
+	GetSpan() SpanLike
 	SetElement(element ElementLike)
 	SetGlyph(glyph GlyphLike)
 	SetPrecedence(precedence PrecedenceLike)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -86,7 +115,49 @@ type CardinalityClassLike interface {
 // supported by all cardinality-like types.
 type CardinalityLike interface {
 	GetConstraint() ConstraintLike
+	GetSpan() SpanLike
 	SetConstraint(constraint ConstraintLike)
+	SetSpan(span SpanLike)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all change-class-like types.
+type ChangeClassLike interface {
+	GetAdded() string
+	GetModified() string
+	GetRemoved() string
+	FromKind(
+		kind string,
+		symbol string,
+		details col.Sequential[string],
+	) ChangeLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all change-like types.  A change-like type records a definition
+// that was added to, removed from or modified between two versions of a
+// grammar.  The details of a modified definition describe each structural
+// change to its alternatives and factors.
+type ChangeLike interface {
+	GetDetails() col.Sequential[string]
+	GetKind() string
+	GetSymbol() string
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all compiler-class-like types.
+type CompilerClassLike interface {
+	FromDocument(document DocumentLike) CompilerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all compiler-like types.  A compiler-like type translates a
+// token definition into an equivalent Go regular expression, resolving any
+// tokens that it references.  An error is returned if the token definition
+// cannot be expressed as a regular expression.  Repetitions of ANY are not
+// greedy, all other repetitions are greedy.
+type CompilerLike interface {
+	CompileDefinition(definition DefinitionLike) (string, error)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -101,8 +172,10 @@ type ConstraintClassLike interface {
 type ConstraintLike interface {
 	GetFirst() string
 	GetLast() string
+	GetSpan() SpanLike
 	SetFirst(first string)
 	SetLast(last string)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -115,11 +188,90 @@ type DefinitionClassLike interface {
 // supported by all definition-like types.
 type DefinitionLike interface {
 	GetExpression() ExpressionLike
+	GetSpan() SpanLike
 	GetSymbol() string
 	SetExpression(expression ExpressionLike)
+	SetSpan(span SpanLike)
 	SetSymbol(symbol string)
 }
 
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all diagnostic-class-like types.
+type DiagnosticClassLike interface {
+	GetConflict() string
+	GetError() string
+	GetInvalid() string
+	GetLeftRecursive() string
+	GetLossy() string
+	GetRecursive() string
+	GetUndefined() string
+	GetUnreachable() string
+	GetUnused() string
+	GetWarning() string
+	FromKind(
+		kind string,
+		severity string,
+		symbol string,
+		message string,
+		span SpanLike,
+	) DiagnosticLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all diagnostic-like types.  A diagnostic-like type describes a
+// single problem found while validating a grammar.  The symbol is empty for
+// problems that are not within a definition.  The span is nil, and the line
+// and position are zero, when the location of the problem is unknown.  The kind
+// categorizes the problem, for example an invalid construct or an undefined
+// name.
+type DiagnosticLike interface {
+	GetKind() string
+	GetLine() int
+	GetMessage() string
+	GetPosition() int
+	GetSeverity() string
+	GetSpan() SpanLike
+	GetSymbol() string
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all diagrammer-class-like types.  The
+// link format determines the link from each name in a diagram to the diagram
+// of its definition, for example "#%v" or "grammar.svg#%v".
+type DiagrammerClassLike interface {
+	Default() DiagrammerLike
+	FromLinkFormat(format string) DiagrammerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all diagrammer-like types.  A diagrammer-like type draws the
+// railroad (syntax) diagram for each definition as a standalone SVG document.
+// A document diagram stacks the diagrams for all of its definitions, each of
+// which is identified by the name being defined.
+type DiagrammerLike interface {
+	DiagramDefinition(definition DefinitionLike) string
+	DiagramDocument(document DocumentLike) string
+	GetLinkFormat() string
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all differ-class-like types.
+type DifferClassLike interface {
+	Default() DifferLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all differ-like types.  A differ-like type compares two versions
+// of a grammar and reports the definitions that were added, removed or
+// modified.  Differences in comments, notes and formatting are ignored, but
+// reordered alternatives are reported as moved since they are tried in order.
+type DifferLike interface {
+	DiffDocuments(
+		oldDocument DocumentLike,
+		newDocument DocumentLike,
+	) col.Sequential[ChangeLike]
+}
+
 // This abstract type defines the set of class constants, constructors and
 // functions that must be supported by all document-class-like types.
 type DocumentClassLike interface {
@@ -130,7 +282,9 @@ type DocumentClassLike interface {
 // supported by all document-like types.
 type DocumentLike interface {
 	GetGrammar() GrammarLike
+	GetSpan() SpanLike
 	SetGrammar(grammar GrammarLike)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -145,11 +299,67 @@ type ElementClassLike interface {
 // supported by all element-like types.
 type ElementLike interface {
 	GetIntrinsic() string
-	GetName() string
 	GetLiteral() string
+	GetName() string
+	GetSpan() SpanLike
 	SetIntrinsic(intrinsic string)
-	SetName(name string)
 	SetLiteral(literal string)
+	SetName(name string)
+	SetSpan(span SpanLike)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all example-class-like types.
+type ExampleClassLike interface {
+	FromSource(source string, isValid bool, description string) ExampleLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all example-like types.  An example-like type is a source that
+// either conforms to a grammar or does not, along with a description of what
+// part of the grammar it exercises.
+type ExampleLike interface {
+	GetDescription() string
+	GetSource() string
+	IsValid() bool
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all exemplifier-class-like types.
+type ExemplifierClassLike interface {
+	FromDocument(document DocumentLike) ExemplifierLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all exemplifier-like types.  An exemplifier-like type generates
+// a conformance suite for the grammar defined by a validated CDSN document.
+// Each valid example is the shortest sentence that exercises one alternative of
+// an expression, one boundary of a cardinality or one end of a glyph.  Each
+// invalid example differs by a count outside of a cardinality, by a character
+// outside of a glyph or by something excluded by an inversion.  The examples
+// are checked using an interpreter and those that it disagrees with are
+// dropped.
+type ExemplifierLike interface {
+	GenerateExamples(symbol string) (col.Sequential[ExampleLike], error)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all exporter-class-like types.
+type ExporterClassLike interface {
+	GetISO() string
+	GetW3C() string
+	FromNotation(notation string) ExporterLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all exporter-like types.  An exporter-like type renders a CDSN
+// document in another grammar notation.  Any construct that the notation
+// cannot express exactly is approximated and reported by a diagnostic from the
+// last export.
+type ExporterLike interface {
+	ExportDocument(document DocumentLike) string
+	GetDiagnostics() col.Sequential[DiagnosticLike]
+	GetNotation() string
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -162,9 +372,11 @@ type ExpressionClassLike interface {
 // supported by all expression-like types.
 type ExpressionLike interface {
 	GetAlternatives() col.Sequential[AlternativeLike]
+	GetSpan() SpanLike
 	IsMultilined() bool
 	SetAlternatives(alternatives col.Sequential[AlternativeLike])
 	SetMultilined(isMultilined bool)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -178,8 +390,10 @@ type FactorClassLike interface {
 type FactorLike interface {
 	GetCardinality() CardinalityLike
 	GetPredicate() PredicateLike
+	GetSpan() SpanLike
 	SetCardinality(cardinality CardinalityLike)
 	SetPredicate(predicate PredicateLike)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -195,6 +409,28 @@ type FormatterLike interface {
 	FormatDocument(document DocumentLike) string
 }
 
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all generator-class-like types.
+type GeneratorClassLike interface {
+	Default() GeneratorLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all generator-like types.  A generator-like type generates the
+// Go source files for a package that parses the notation defined by a CDSN
+// document.  The resulting catalog maps each file name to its source code.  It
+// can also generate an equivalent ANTLR4 combined grammar from the document.
+type GeneratorLike interface {
+	GenerateANTLR(
+		grammarName string,
+		document DocumentLike,
+	) (string, error)
+	GeneratePackage(
+		packageName string,
+		document DocumentLike,
+	) (col.CatalogLike[string, string], error)
+}
+
 // This abstract type defines the set of class constants, constructors and
 // functions that must be supported by all glyph-class-like types.
 type GlyphClassLike interface {
@@ -207,8 +443,10 @@ type GlyphClassLike interface {
 type GlyphLike interface {
 	GetFirst() string
 	GetLast() string
+	GetSpan() SpanLike
 	SetFirst(first string)
 	SetLast(last string)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -218,12 +456,140 @@ type GrammarClassLike interface {
 }
 
 // This abstract type defines the set of abstract interfaces that must be
-// supported by all grammar-like types.
+// supported by all grammar-like types.  A grammar-like type can also inline a
+// definition into each of its references or extract a range of factors from an
+// alternative into a new definition.  Any other occurrences of the extracted
+// factors within the same alternative also refer to the new definition.
 type GrammarLike interface {
+	Extract(
+		definition DefinitionLike,
+		alternative int,
+		first int,
+		last int,
+		symbol string,
+	)
+	GetSpan() SpanLike
 	GetStatements() col.Sequential[StatementLike]
+	Inline(symbol string)
+	SetSpan(span SpanLike)
 	SetStatements(statements col.Sequential[StatementLike])
 }
 
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all importer-class-like types.
+type ImporterClassLike interface {
+	GetABNF() string
+	FromNotation(notation string) ImporterLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all importer-like types.  An importer-like type converts a
+// grammar written in another notation into a CDSN document.  Any construct that
+// needs manual attention is reported by a diagnostic from the last import.
+type ImporterLike interface {
+	GetDiagnostics() col.Sequential[DiagnosticLike]
+	GetNotation() string
+	ImportSource(source string) (DocumentLike, error)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all interpreter-class-like types.
+type InterpreterClassLike interface {
+	FromDocument(document DocumentLike) InterpreterLike
+	FromDocumentAndSymbol(document DocumentLike, symbol string) InterpreterLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all interpreter-like types.  An interpreter-like type parses
+// source text against the grammar defined by a validated CDSN document starting
+// with the rule definition for its symbol.
+type InterpreterLike interface {
+	GetSymbol() string
+	ParseSource(source string) (NodeLike, error)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all location-class-like types.
+type LocationClassLike interface {
+	FromPosition(line int, position int, offset int) LocationLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all location-like types.  A location-like type identifies a
+// rune within a document by its line number and position within that line,
+// both starting at one, and by its zero based byte offset into the document.
+type LocationLike interface {
+	GetLine() int
+	GetOffset() int
+	GetPosition() int
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all node-class-like types.
+type NodeClassLike interface {
+	FromRule(
+		name string,
+		text string,
+		children col.Sequential[NodeLike],
+		span SpanLike,
+	) NodeLike
+	FromToken(name string, text string, span SpanLike) NodeLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all node-like types.  A node-like type is a node in the concrete
+// syntax tree that results from interpreting source text against a grammar.
+type NodeLike interface {
+	GetChildren() col.Sequential[NodeLike]
+	GetName() string
+	GetSpan() SpanLike
+	GetText() string
+	IsToken() bool
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all normalizer-class-like types.
+type NormalizerClassLike interface {
+	Default() NormalizerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all normalizer-like types.  A normalizer-like type transforms
+// the definitions in a CDSN document in place into simpler forms that define
+// the same language, for use by generators that support fewer constructs.
+// Expanding the constraints leaves "?", "*" and "+" as the only cardinalities.
+// Extracting the precedences replaces each precedence within a rule, other
+// than an inverted one, with a new helper rule.  Flattening the precedences
+// removes those containing a single alternative where possible.  Removing the
+// repetitions replaces each repetition within a rule with a new right
+// recursive helper rule, leaving "?" as the only cardinality, and refuses a
+// repetition of something that can match nothing.  The helper rules are named
+// after the rule that uses them and follow it.  Since a token cannot be
+// recursive, removing the repetitions only expands the constraints of a token
+// definition and keeps its "*" and "+" repetitions.
+type NormalizerLike interface {
+	ExpandConstraints(document DocumentLike)
+	ExtractPrecedences(document DocumentLike)
+	FlattenPrecedences(document DocumentLike)
+	RemoveRepetitions(document DocumentLike)
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all parse-error-like types.  A parse-error-like type describes
+// the location and cause of a parsing failure.  The colored rendering includes
+// the surrounding lines of the document and the expected grammatical rules.
+type ParseErrorLike interface {
+	error
+	FormatColored() string
+	GetExpected() string
+	GetLine() int
+	GetMessage() string
+	GetPosition() int
+	GetRules() col.Sequential[string]
+	GetTokenType() string
+	GetTokenValue() string
+}
+
 // This abstract type defines the set of class constants, constructors and
 // functions that must be supported by all parser-class-like types.
 type ParserClassLike interface {
@@ -234,6 +600,7 @@ type ParserClassLike interface {
 // supported by all parser-like types.
 type ParserLike interface {
 	ParseDocument(document string) DocumentLike
+	ParseDocumentSafely(document string) (DocumentLike, error)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -246,7 +613,9 @@ type PrecedenceClassLike interface {
 // supported by all precedence-like types.
 type PrecedenceLike interface {
 	GetExpression() ExpressionLike
+	GetSpan() SpanLike
 	SetExpression(expression ExpressionLike)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -259,9 +628,112 @@ type PredicateClassLike interface {
 // supported by all predicate-like types.
 type PredicateLike interface {
 	GetAssertion() AssertionLike
+	GetSpan() SpanLike
 	IsInverted() bool
 	SetAssertion(assertion AssertionLike)
 	SetInverted(inverted bool)
+	SetSpan(span SpanLike)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all publisher-class-like types.
+type PublisherClassLike interface {
+	Default() PublisherLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all publisher-like types.  A publisher-like type publishes the
+// reference documentation for a CDSN document as a single self-contained HTML
+// page.  Each comment becomes a section of prose and each definition has an
+// anchor that the names within the other definitions link to.  The page ends
+// with an index listing the definitions that use each name.
+type PublisherLike interface {
+	PublishDocument(document DocumentLike) string
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all refactorer-class-like types.
+type RefactorerClassLike interface {
+	Default() RefactorerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all refactorer-like types.  A refactorer-like type restructures
+// the definitions in a CDSN document without changing the language that it
+// defines.  The document is modified in place and the reformatted document is
+// returned.  A symbol may only be renamed to one that is not already defined
+// and that names the same kind of definition, a token or a rule, since the
+// case of its first letter determines how it is matched.
+type RefactorerLike interface {
+	RenameSymbol(
+		document DocumentLike,
+		oldSymbol string,
+		newSymbol string,
+	) (string, error)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all sampler-class-like types.
+type SamplerClassLike interface {
+	FromDocument(document DocumentLike) SamplerLike
+	FromDocumentAndSeed(document DocumentLike, seed int64) SamplerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all sampler-like types.  A sampler-like type generates random
+// sentences that conform to the grammar defined by a validated CDSN document,
+// for use as a fuzzing corpus.  The same seed always produces the same sequence
+// of sentences.  Unlimited repetitions are bounded by the maximum repetitions,
+// and beyond the maximum depth the alternatives that complete soonest are
+// chosen so that recursive definitions terminate.  A sentence for a rule is
+// sampled again if the longest terminals, such as a note that runs to the end
+// of its line, would not scan it as it was sampled.
+type SamplerLike interface {
+	GetMaximumDepth() int
+	GetMaximumRepetitions() int
+	GetSeed() int64
+	SampleSentence(symbol string) (string, error)
+	SetMaximumDepth(depth int)
+	SetMaximumRepetitions(repetitions int)
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all serializer-class-like types.
+type SerializerClassLike interface {
+	GetJSON() string
+	GetYAML() string
+	FromFormat(format string) SerializerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all serializer-like types.  A serializer-like type encodes a
+// parse tree as a JSON or YAML document and decodes it again.  Each node is
+// encoded as an object with a field for each of its attributes, named after
+// the getter method for the attribute (e.g. "statements", "symbol", "note",
+// "multilined" or "inverted").  An assertion contains exactly one "element",
+// "glyph" or "precedence" field, an element contains exactly one "intrinsic",
+// "literal" or "name" field, and a statement contains exactly one "comment" or
+// "definition" field.  The "last" field of a constraint is empty when the
+// number of repetitions is unlimited.  Source spans are not encoded.
+type SerializerLike interface {
+	DeserializeDocument(source string) (DocumentLike, error)
+	GetFormat() string
+	SerializeDocument(document DocumentLike) string
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all span-class-like types.
+type SpanClassLike interface {
+	FromLocations(start, end LocationLike) SpanLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all span-like types.  A span-like type identifies the range of
+// source text from which a node in the parse tree was parsed.  The end location
+// is exclusive, it identifies the rune just past the last rune in the span.
+type SpanLike interface {
+	GetEnd() LocationLike
+	GetStart() LocationLike
 }
 
 // This abstract type defines the set of class constants, constructors and
@@ -276,18 +748,88 @@ type StatementClassLike interface {
 type StatementLike interface {
 	GetComment() string
 	GetDefinition() DefinitionLike
+	GetSpan() SpanLike
 	SetComment(comment string)
 	SetDefinition(definition DefinitionLike)
+	SetSpan(span SpanLike)
 }
 
 // This abstract type defines the set of class constants, constructors and
 // functions that must be supported by all validator-class-like types.
 type ValidatorClassLike interface {
 	Default() ValidatorLike
+	Strict() ValidatorLike
 }
 
 // This abstract type defines the set of abstract interfaces that must be
-// supported by all validator-like types.
+// supported by all validator-like types.  A strict validator also requires that
+// no rule is left recursive and that each choice within a rule can be made by
+// looking at the next token (LL(1)).
 type ValidatorLike interface {
+	DiagnoseDocument(document DocumentLike) col.Sequential[DiagnosticLike]
 	ValidateDocument(document DocumentLike)
 }
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all visitor-class-like types.
+type VisitorClassLike interface {
+	Default() VisitorLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all visitor-like types.  A visitor-like type is notified by a
+// walker before and after each node in a parse tree is walked.  The nodes
+// within a node are skipped if its pre-hook returns false, in which case its
+// post-hook is not called either.  The default visitor does nothing and visits
+// every node, it may be embedded in a type that overrides only the hooks it
+// needs.
+type VisitorLike interface {
+	PostAlternative(alternative AlternativeLike, walker WalkerLike)
+	PostAssertion(assertion AssertionLike, walker WalkerLike)
+	PostCardinality(cardinality CardinalityLike, walker WalkerLike)
+	PostConstraint(constraint ConstraintLike, walker WalkerLike)
+	PostDefinition(definition DefinitionLike, walker WalkerLike)
+	PostDocument(document DocumentLike, walker WalkerLike)
+	PostElement(element ElementLike, walker WalkerLike)
+	PostExpression(expression ExpressionLike, walker WalkerLike)
+	PostFactor(factor FactorLike, walker WalkerLike)
+	PostGlyph(glyph GlyphLike, walker WalkerLike)
+	PostGrammar(grammar GrammarLike, walker WalkerLike)
+	PostPrecedence(precedence PrecedenceLike, walker WalkerLike)
+	PostPredicate(predicate PredicateLike, walker WalkerLike)
+	PostStatement(statement StatementLike, walker WalkerLike)
+	PreAlternative(alternative AlternativeLike, walker WalkerLike) bool
+	PreAssertion(assertion AssertionLike, walker WalkerLike) bool
+	PreCardinality(cardinality CardinalityLike, walker WalkerLike) bool
+	PreConstraint(constraint ConstraintLike, walker WalkerLike) bool
+	PreDefinition(definition DefinitionLike, walker WalkerLike) bool
+	PreDocument(document DocumentLike, walker WalkerLike) bool
+	PreElement(element ElementLike, walker WalkerLike) bool
+	PreExpression(expression ExpressionLike, walker WalkerLike) bool
+	PreFactor(factor FactorLike, walker WalkerLike) bool
+	PreGlyph(glyph GlyphLike, walker WalkerLike) bool
+	PreGrammar(grammar GrammarLike, walker WalkerLike) bool
+	PrePrecedence(precedence PrecedenceLike, walker WalkerLike) bool
+	PrePredicate(predicate PredicateLike, walker WalkerLike) bool
+	PreStatement(statement StatementLike, walker WalkerLike) bool
+}
+
+// This abstract type defines the set of class constants, constructors and
+// functions that must be supported by all walker-class-like types.
+type WalkerClassLike interface {
+	FromVisitor(visitor VisitorLike) WalkerLike
+}
+
+// This abstract type defines the set of abstract interfaces that must be
+// supported by all walker-like types.  A walker-like type walks a parse tree
+// depth first, in document order, notifying its visitor of each node.  While
+// walking, the walker knows the definition enclosing the current node (nil if
+// there is none) and the depth of the current node, where the node being
+// walked has a depth of zero.
+type WalkerLike interface {
+	GetDefinition() DefinitionLike
+	GetDepth() int
+	WalkDefinition(definition DefinitionLike)
+	WalkDocument(document DocumentLike)
+	WalkExpression(expression ExpressionLike)
+}
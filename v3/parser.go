@@ -11,9 +11,7 @@
 package cdsn
 
 import (
-	fmt "fmt"
 	col "github.com/craterdog/go-collection-framework/v3"
-	sts "strings"
 )
 
 // CLASS NAMESPACE
@@ -42,8 +40,7 @@ func ParserClass() ParserClassLike {
 
 func (c *parserClass_) Default() ParserLike {
 	var parser = &parser_{
-		names: col.CatalogClass[string, string]().Empty(),
-		next:  col.StackClass[*token_]().WithCapacity(c.stackSize),
+		// This class does not initialize any attributes.
 	}
 	return parser
 }
@@ -53,39 +50,79 @@ func (c *parserClass_) Default() ParserLike {
 // Private Class Type Definition
 
 type parser_ struct {
-	document string
-	names    col.CatalogLike[string, string]
-	next     col.StackLike[*token_] // A stack of unprocessed retrieved tokens.
-	tokens   chan *token_           // A queue of unread tokens from the scanner.
+	consumed   []*token_ // A stack of the tokens consumed by the parser.
+	document   string
+	names      col.CatalogLike[string, string]
+	next       col.StackLike[*token_]           // A stack of unprocessed retrieved tokens.
+	references col.CatalogLike[string, *token_] // The first reference to each name.
+	tokens     chan *token_                     // A queue of unread tokens from the scanner.
 }
 
 // Public Interface
 
 func (v *parser_) ParseDocument(document string) DocumentLike {
+	var result, err = v.parseDocument(document)
+	if err != nil {
+		panic(err.FormatColored())
+	}
+	return result
+}
+
+func (v *parser_) ParseDocumentSafely(document string) (DocumentLike, error) {
+	var result, err = v.parseDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Private Interface
+
+// This private class method performs the actual parsing of the specified
+// document.  Any parsing errors are recovered and returned as a parse error.
+func (v *parser_) parseDocument(document string) (
+	result DocumentLike,
+	err *parseError_,
+) {
 	// Start a scanner running in a separate Go routine.
+	v.consumed = nil
 	v.document = document
+	v.names = col.CatalogClass[string, string]().Empty()
+	v.next = col.StackClass[*token_]().WithCapacity(parserClass.stackSize)
+	v.references = col.CatalogClass[string, *token_]().Empty()
 	v.tokens = make(chan *token_, parserClass.channelSize)
 	ScannerClass().FromDocument(v.document, v.tokens)
+	defer func() {
+		if e := recover(); e != nil {
+			var ok bool
+			err, ok = e.(*parseError_)
+			if !ok {
+				panic(e)
+			}
+			// Allow the scanner to finish.
+			go func(tokens chan *token_) {
+				for range tokens {
+				}
+			}(v.tokens)
+		}
+	}()
 
 	// Parse the tokens from the scanner.
 	var grammar, token, ok = v.parseGrammar()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("grammar",
+		panic(v.unexpectedToken(token, "grammar",
 			"$document",
 			"$grammar",
-		)
-		panic(message)
+		))
 	}
 	_, token, ok = v.parseEOF()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("EOF",
+		panic(v.unexpectedToken(token, "EOF",
 			"$document",
 			"$grammar",
-		)
-		panic(message)
+		))
 	}
+	var start = LocationClass().FromPosition(1, 1, 0)
 
 	// Make sure all names have associated definitions.
 	var iterator = v.names.GetIterator()
@@ -94,63 +131,29 @@ func (v *parser_) ParseDocument(document string) DocumentLike {
 		var name = association.GetKey()
 		var definition = association.GetValue()
 		if len(definition) == 0 {
-			var message = fmt.Sprintf(
-				"The grammar is missing a definition for name: %v\n",
-				name,
-			)
-			panic(message)
+			token = v.references.GetValue(name)
+			panic(parseErrorClass.fromMissingDefinition(v.document, token))
 		}
 	}
 
-	return DocumentClass().FromGrammar(grammar)
+	result = DocumentClass().FromGrammar(grammar)
+	result.SetSpan(v.spanFrom(start))
+	return result, nil
 }
 
-// Private Interface
-
-// This private class method returns an error message containing the context for
-// a parsing error.
-func (v *parser_) formatError(token *token_) string {
-	var message = fmt.Sprintf(
-		"An unexpected token was received by the parser: %v\n",
+// This private class method returns a parse error describing a token that was
+// not expected by the parser along with the required grammatical rules.
+func (v *parser_) unexpectedToken(
+	token *token_,
+	expected string,
+	symbols ...string,
+) *parseError_ {
+	return parseErrorClass.fromUnexpectedToken(
+		v.document,
 		token,
+		expected,
+		symbols...,
 	)
-	var line = token.GetLine()
-	var lines = sts.Split(v.document, "\n")
-
-	message += "\033[36m"
-	if line > 1 {
-		message += fmt.Sprintf("%04d: ", line-1) + string(lines[line-2]) + "\n"
-	}
-	message += fmt.Sprintf("%04d: ", line) + string(lines[line-1]) + "\n"
-
-	message += " \033[32m>>>─"
-	var count = 0
-	for count < token.GetPosition() {
-		message += "─"
-		count++
-	}
-	message += "⌃\033[36m\n"
-
-	if line < len(lines) {
-		message += fmt.Sprintf("%04d: ", line+1) + string(lines[line]) + "\n"
-	}
-	message += "\033[0m\n"
-
-	return message
-}
-
-// This private class method is useful when creating scanner and parser error
-// messages that include the required grammatical rules.
-func (v *parser_) generateGrammar(expected string, symbols ...string) string {
-	var message = "Was expecting '" + expected + "' from:\n"
-	for _, symbol := range symbols {
-		message += fmt.Sprintf(
-			"  \033[32m%v: \033[33m%v\033[0m\n\n",
-			symbol,
-			grammar[symbol],
-		)
-	}
-	return message
 }
 
 // This private class method attempts to read the next token from the token
@@ -164,12 +167,12 @@ func (v *parser_) getNextToken() *token_ {
 		}
 		next = token
 		if next.GetType() == TokenClass().GetError() {
-			var message = v.formatError(next)
-			panic(message)
+			panic(parseErrorClass.fromInvalidToken(v.document, next))
 		}
 	} else {
 		next = v.next.RemoveTop()
 	}
+	v.consumed = append(v.consumed, next)
 	return next
 }
 
@@ -185,6 +188,7 @@ func (v *parser_) parseAlternative() (AlternativeLike, *token_, bool) {
 		// This is not an alternative.
 		return alternative, token, false
 	}
+	var start = factor.GetSpan().GetStart()
 	for {
 		factors.AppendValue(factor)
 		factor, _, ok = v.parseFactor()
@@ -195,6 +199,7 @@ func (v *parser_) parseAlternative() (AlternativeLike, *token_, bool) {
 			if ok {
 				alternative.SetNote(note)
 			}
+			alternative.SetSpan(v.spanFrom(start))
 			return alternative, token, true
 		}
 	}
@@ -210,16 +215,19 @@ func (v *parser_) parseAssertion() (AssertionLike, *token_, bool) {
 	element, token, ok = v.parseElement()
 	if ok {
 		assertion = AssertionClass().FromElement(element)
+		assertion.SetSpan(element.GetSpan())
 		return assertion, token, true
 	}
 	glyph, token, ok = v.parseGlyph()
 	if ok {
 		assertion = AssertionClass().FromGlyph(glyph)
+		assertion.SetSpan(glyph.GetSpan())
 		return assertion, token, true
 	}
 	precedence, token, ok = v.parsePrecedence()
 	if ok {
 		assertion = AssertionClass().FromPrecedence(precedence)
+		assertion.SetSpan(precedence.GetSpan())
 		return assertion, token, true
 	}
 	return assertion, token, false
@@ -233,42 +241,46 @@ func (v *parser_) parseCardinality() (CardinalityLike, *token_, bool) {
 	_, token, ok = v.parseDelimiter("?")
 	if ok {
 		constraint = ConstraintClass().FromRange("0", "1")
+		constraint.SetSpan(token.GetSpan())
 		cardinality = CardinalityClass().FromConstraint(constraint)
+		cardinality.SetSpan(token.GetSpan())
 		return cardinality, token, true
 	}
 	_, token, ok = v.parseDelimiter("*")
 	if ok {
 		constraint = ConstraintClass().FromRange("0", "")
+		constraint.SetSpan(token.GetSpan())
 		cardinality = CardinalityClass().FromConstraint(constraint)
+		cardinality.SetSpan(token.GetSpan())
 		return cardinality, token, true
 	}
 	_, token, ok = v.parseDelimiter("+")
 	if ok {
 		constraint = ConstraintClass().FromRange("1", "")
+		constraint.SetSpan(token.GetSpan())
 		cardinality = CardinalityClass().FromConstraint(constraint)
+		cardinality.SetSpan(token.GetSpan())
 		return cardinality, token, true
 	}
 	_, token, ok = v.parseDelimiter("{")
 	if ok {
+		var start = token.GetSpan().GetStart()
 		constraint, token, ok = v.parseConstraint()
 		if !ok {
-			var message = v.formatError(token)
-			message += v.generateGrammar("constraint",
+			panic(v.unexpectedToken(token, "constraint",
 				"$cardinality",
 				"$constraint",
-			)
-			panic(message)
+			))
 		}
 		_, token, ok = v.parseDelimiter("}")
 		if !ok {
-			var message = v.formatError(token)
-			message += v.generateGrammar("}",
+			panic(v.unexpectedToken(token, "}",
 				"$cardinality",
 				"$constraint",
-			)
-			panic(message)
+			))
 		}
 		cardinality = CardinalityClass().FromConstraint(constraint)
+		cardinality.SetSpan(v.spanFrom(start))
 		return cardinality, token, true
 	}
 	return cardinality, token, false
@@ -306,13 +318,16 @@ func (v *parser_) parseConstraint() (ConstraintLike, *token_, bool) {
 		// This is not a constraint.
 		return constraint, token, false
 	}
+	var start = token.GetSpan().GetStart()
 	_, _, ok = v.parseDelimiter("..")
 	if ok {
 		last, token, _ = v.parseNumber() // The last number is optional.
 		constraint = ConstraintClass().FromRange(first, last)
+		constraint.SetSpan(v.spanFrom(start))
 		return constraint, token, true
 	}
 	constraint = ConstraintClass().FromNumber(first)
+	constraint.SetSpan(v.spanFrom(start))
 	return constraint, token, true
 }
 
@@ -327,33 +342,28 @@ func (v *parser_) parseDefinition() (DefinitionLike, *token_, bool) {
 		// This is not a definition.
 		return definition, token, false
 	}
+	var start = token.GetSpan()
 	var name = symbol[1:]
 	var existing = v.names.GetValue(name)
 	if len(existing) > 0 {
-		var message = v.formatError(token)
-		message += "This symbol has already been defined in this grammar:\n"
-		message += "    " + existing + "\n"
-		panic(message)
+		panic(parseErrorClass.fromDuplicateSymbol(v.document, token, existing))
 	}
 	_, token, ok = v.parseDelimiter(":")
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar(":",
+		panic(v.unexpectedToken(token, ":",
 			"$definition",
 			"$expression",
-		)
-		panic(message)
+		))
 	}
 	expression, token, ok = v.parseExpression()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("expression",
+		panic(v.unexpectedToken(token, "expression",
 			"$definition",
 			"$expression",
-		)
-		panic(message)
+		))
 	}
 	definition = DefinitionClass().FromSymbolAndExpression(symbol, expression)
+	definition.SetSpan(v.spanOver(start, expression.GetSpan()))
 	var formatter = FormatterClass().Default()
 	v.names.SetValue(name, formatter.FormatDefinition(definition))
 	return definition, token, true
@@ -378,16 +388,19 @@ func (v *parser_) parseElement() (ElementLike, *token_, bool) {
 	intrinsic, token, ok = v.parseIntrinsic()
 	if ok {
 		element = ElementClass().FromIntrinsic(intrinsic)
+		element.SetSpan(token.GetSpan())
 		return element, token, true
 	}
 	literal, token, ok = v.parseLiteral()
 	if ok {
 		element = ElementClass().FromLiteral(literal)
+		element.SetSpan(token.GetSpan())
 		return element, token, true
 	}
 	name, token, ok = v.parseName()
 	if ok {
 		element = ElementClass().FromName(name)
+		element.SetSpan(token.GetSpan())
 		return element, token, true
 	}
 	return element, token, false
@@ -421,6 +434,7 @@ func (v *parser_) parseExpression() (ExpressionLike, *token_, bool) {
 	// Handle in-line case.
 	alternative, token, ok = v.parseAlternative()
 	if ok {
+		var first = alternative.GetSpan()
 		for {
 			alternatives.AppendValue(alternative)
 			_, token, ok = v.parseDelimiter("|")
@@ -428,16 +442,15 @@ func (v *parser_) parseExpression() (ExpressionLike, *token_, bool) {
 				// No more alternatives.
 				expression = ExpressionClass().FromAlternatives(alternatives)
 				expression.SetMultilined(false)
+				expression.SetSpan(v.spanOver(first, alternative.GetSpan()))
 				return expression, token, true
 			}
 			alternative, token, ok = v.parseAlternative()
 			if !ok {
-				var message = v.formatError(token)
-				message += v.generateGrammar("alternative",
+				panic(v.unexpectedToken(token, "alternative",
 					"$expression",
 					"$alternative",
-				)
-				panic(message)
+				))
 			}
 		}
 	}
@@ -450,31 +463,31 @@ func (v *parser_) parseExpression() (ExpressionLike, *token_, bool) {
 	}
 	alternative, token, ok = v.parseAlternative()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("alternative",
+		panic(v.unexpectedToken(token, "alternative",
 			"$expression",
 			"$alternative",
-		)
-		panic(message)
+		))
 	}
+	var first = alternative.GetSpan()
+	var last = alternative.GetSpan()
 	for {
 		alternatives.AppendValue(alternative)
 		var _, token, ok = v.parseEOL()
 		if !ok {
-			var message = v.formatError(token)
-			message += v.generateGrammar("EOL",
+			panic(v.unexpectedToken(token, "EOL",
 				"$expression",
 				"$alternative",
-			)
-			panic(message)
+			))
 		}
 		alternative, token, ok = v.parseAlternative()
 		if !ok {
 			// No more alternatives.
 			expression = ExpressionClass().FromAlternatives(alternatives)
 			expression.SetMultilined(true)
+			expression.SetSpan(v.spanOver(first, last))
 			return expression, token, true
 		}
+		last = alternative.GetSpan()
 	}
 }
 
@@ -490,10 +503,12 @@ func (v *parser_) parseFactor() (FactorLike, *token_, bool) {
 		return factor, token, false
 	}
 	factor = FactorClass().FromPredicate(predicate)
+	factor.SetSpan(predicate.GetSpan())
 	cardinality, token, ok = v.parseCardinality()
 	if ok {
 		// The cardinality is optional.
 		factor.SetCardinality(cardinality)
+		factor.SetSpan(v.spanOver(predicate.GetSpan(), cardinality.GetSpan()))
 	}
 	return factor, token, true
 }
@@ -508,21 +523,22 @@ func (v *parser_) parseGlyph() (GlyphLike, *token_, bool) {
 		// This is not a glyph.
 		return glyph, token, false
 	}
+	var start = token.GetSpan().GetStart()
 	_, _, ok = v.parseDelimiter("..")
 	if !ok {
 		// The range of characters is optional.
 		glyph = GlyphClass().FromCharacter(first)
+		glyph.SetSpan(v.spanFrom(start))
 		return glyph, token, true
 	}
 	last, token, ok = v.parseCharacter()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("glyph",
+		panic(v.unexpectedToken(token, "glyph",
 			"$glyph",
-		)
-		panic(message)
+		))
 	}
 	glyph = GlyphClass().FromRange(first, last)
+	glyph.SetSpan(v.spanFrom(start))
 	return glyph, token, true
 }
 
@@ -532,22 +548,30 @@ func (v *parser_) parseGrammar() (GrammarLike, *token_, bool) {
 	var grammar GrammarLike
 	var statement StatementLike
 	var statements = col.ListClass[StatementLike]().Empty()
+	var first, last SpanLike
 	for {
 		statement, token, ok = v.parseStatement()
 		if !ok {
+			if statements.IsEmpty() {
+				// A grammar must contain at least one statement.
+				return grammar, token, false
+			}
 			// There are no more statements.
 			grammar = GrammarClass().FromStatements(statements)
+			grammar.SetSpan(v.spanOver(first, last))
 			return grammar, token, true
 		}
+		if first == nil {
+			first = statement.GetSpan()
+		}
+		last = statement.GetSpan()
 		statements.AppendValue(statement)
 		_, token, ok = v.parseEOL()
 		if !ok {
-			var message = v.formatError(token)
-			message += v.generateGrammar("EOL",
+			panic(v.unexpectedToken(token, "EOL",
 				"$grammar",
 				"$statement",
-			)
-			panic(message)
+			))
 		}
 		for ok {
 			// Absorb any blank lines.
@@ -588,6 +612,9 @@ func (v *parser_) parseName() (string, *token_, bool) {
 	name = token.GetValue()
 	var definition = v.names.GetValue(name) // Returns "" if not found.
 	v.names.SetValue(name, definition)
+	if v.references.GetValue(name) == nil {
+		v.references.SetValue(name, token)
+	}
 	return name, token, true
 }
 
@@ -623,25 +650,23 @@ func (v *parser_) parsePrecedence() (PrecedenceLike, *token_, bool) {
 		// This is not a precedence.
 		return precedence, token, false
 	}
+	var start = token.GetSpan().GetStart()
 	expression, token, ok = v.parseExpression()
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar("expression",
+		panic(v.unexpectedToken(token, "expression",
 			"$precedence",
 			"$expression",
-		)
-		panic(message)
+		))
 	}
 	_, token, ok = v.parseDelimiter(")")
 	if !ok {
-		var message = v.formatError(token)
-		message += v.generateGrammar(")",
+		panic(v.unexpectedToken(token, ")",
 			"$precedence",
 			"$expression",
-		)
-		panic(message)
+		))
 	}
 	precedence = PrecedenceClass().FromExpression(expression)
+	precedence.SetSpan(v.spanFrom(start))
 	return precedence, token, true
 }
 
@@ -651,23 +676,24 @@ func (v *parser_) parsePredicate() (PredicateLike, *token_, bool) {
 	var isInverted bool
 	var assertion AssertionLike
 	var predicate PredicateLike
-	_, _, isInverted = v.parseDelimiter("~")
+	_, token, isInverted = v.parseDelimiter("~")
 	if isInverted {
+		var start = token.GetSpan().GetStart()
 		assertion, token, ok = v.parseAssertion()
 		if ok {
 			predicate = PredicateClass().FromAssertion(assertion, isInverted)
+			predicate.SetSpan(v.spanFrom(start))
 			return predicate, token, true
 		}
-		var message = v.formatError(token)
-		message += v.generateGrammar("assertion",
+		panic(v.unexpectedToken(token, "assertion",
 			"$predicate",
 			"$assertion",
-		)
-		panic(message)
+		))
 	}
 	assertion, token, ok = v.parseAssertion()
 	if ok {
 		predicate = PredicateClass().FromAssertion(assertion, isInverted)
+		predicate.SetSpan(assertion.GetSpan())
 		return predicate, token, true
 	}
 	return predicate, token, false
@@ -682,11 +708,13 @@ func (v *parser_) parseStatement() (StatementLike, *token_, bool) {
 	comment, token, ok = v.parseComment()
 	if ok {
 		statement = StatementClass().FromComment(comment)
+		statement.SetSpan(token.GetSpan())
 		return statement, token, true
 	}
 	definition, token, ok = v.parseDefinition()
 	if ok {
 		statement = StatementClass().FromDefinition(definition)
+		statement.SetSpan(definition.GetSpan())
 		return statement, token, true
 	}
 	return statement, token, false
@@ -705,6 +733,21 @@ func (v *parser_) parseSymbol() (string, *token_, bool) {
 
 func (v *parser_) putBack(token *token_) {
 	v.next.AddValue(token)
+	v.consumed = v.consumed[:len(v.consumed)-1]
+}
+
+// This private class method returns a span starting at the specified location
+// and ending with the last token consumed by the parser.
+func (v *parser_) spanFrom(start LocationLike) SpanLike {
+	var last = v.consumed[len(v.consumed)-1]
+	var end = last.GetSpan().GetEnd()
+	return SpanClass().FromLocations(start, end)
+}
+
+// This private class method returns a span starting with the first specified
+// span and ending with the last specified span.
+func (v *parser_) spanOver(first, last SpanLike) SpanLike {
+	return SpanClass().FromLocations(first.GetStart(), last.GetEnd())
 }
 
 var grammar = map[string]string{
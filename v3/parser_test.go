@@ -13,8 +13,17 @@ package cdsn_test
 import (
 	fmt "fmt"
 	cds "github.com/craterdog/go-cdsn-validation/v3"
+	col "github.com/craterdog/go-collection-framework/v3"
 	ass "github.com/stretchr/testify/assert"
+	ast "go/ast"
+	imp "go/importer"
+	prs "go/parser"
+	tok "go/token"
+	typ "go/types"
 	osx "os"
+	exe "os/exec"
+	pth "path/filepath"
+	reg "regexp"
 	sts "strings"
 	tes "testing"
 )
@@ -187,3 +196,1494 @@ $WORSE: CONTROL
 
 	validator.ValidateDocument(parser.ParseDocument(document))
 }
+
+func TestParseErrors(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document, err = parser.ParseDocumentSafely(`$BAD: ~~CONTROL
+`)
+	ass.Nil(t, document)
+	var parseError, ok = err.(cds.ParseErrorLike)
+	ass.True(t, ok)
+	ass.Equal(t, 1, parseError.GetLine())
+	ass.Equal(t, 8, parseError.GetPosition())
+	ass.Equal(t, "Delimiter", parseError.GetTokenType())
+	ass.Equal(t, "~", parseError.GetTokenValue())
+	ass.Equal(t, "assertion", parseError.GetExpected())
+	ass.Equal(t, []string{"$predicate", "$assertion"}, parseError.GetRules().AsArray())
+	ass.Equal(
+		t,
+		"1:8: An unexpected Delimiter token \"~\" was received, was expecting 'assertion' from: $predicate, $assertion",
+		parseError.Error(),
+	)
+
+	_, err = parser.ParseDocumentSafely(`$bad: "bad"
+$worse: rule
+`)
+	ass.Equal(t, "2:9: The grammar is missing a definition for name: rule", err.Error())
+
+	_, err = parser.ParseDocumentSafely("$bad: `bad`\n")
+	parseError = err.(cds.ParseErrorLike)
+	ass.True(t, sts.HasPrefix(parseError.FormatColored(), "An invalid character was found by the scanner: "))
+
+	document, err = parser.ParseDocumentSafely(`$good: "good"
+`)
+	ass.Nil(t, err)
+	ass.NotNil(t, document)
+}
+
+func TestDiagnoseDocument(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var validator = cds.ValidatorClass().Default()
+	var document = parser.ParseDocument(`$BAD: rule
+$rule: ~"ow"
+$worse: ~rule
+`)
+	var diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, 3, len(diagnostics))
+	ass.Equal(t, "$BAD", diagnostics[0].GetSymbol())
+	ass.Equal(t, cds.DiagnosticClass().GetError(), diagnostics[0].GetSeverity())
+	ass.Equal(t, "A token definition cannot contain a rule name.", diagnostics[0].GetMessage())
+	ass.Equal(t, 1, diagnostics[0].GetLine())
+	ass.Equal(t, 7, diagnostics[0].GetPosition())
+	ass.Equal(t, "$rule", diagnostics[1].GetSymbol())
+	ass.Equal(t, "A multi-character literal is not allowed in an inversion.", diagnostics[1].GetMessage())
+	ass.Equal(t, 2, diagnostics[1].GetLine())
+	ass.Equal(t, 9, diagnostics[1].GetPosition())
+	ass.Equal(t, "$worse", diagnostics[2].GetSymbol())
+	ass.Equal(t, "An inverted assertion cannot contain a rule name.", diagnostics[2].GetMessage())
+	ass.Equal(t, 3, diagnostics[2].GetLine())
+	ass.Equal(t, 10, diagnostics[2].GetPosition())
+
+	// An invalid symbol does not inherit the kind of the previous definition.
+	document = parser.ParseDocument(`$BAD: "bad"
+$rule: other
+$other: "other"
+`)
+	var definition = document.GetGrammar().GetStatements().AsArray()[1].GetDefinition()
+	definition.SetSymbol("$9rule")
+	diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, "$9rule", diagnostics[0].GetSymbol())
+	ass.Equal(t, "Found an invalid symbol.", diagnostics[0].GetMessage())
+	for _, diagnostic := range diagnostics {
+		ass.NotEqual(t, "A token definition cannot contain a rule name.", diagnostic.GetMessage())
+	}
+}
+
+func TestSourceSpans(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`!> Ä comment. <!
+$list: "[" ~'é'..'ü'{2..3} "]"
+$items:
+    list+  ! One or more.
+    "none"
+
+`)
+	var formatSpan = func(span cds.SpanLike) string {
+		var start = span.GetStart()
+		var end = span.GetEnd()
+		return fmt.Sprintf(
+			"%v:%v@%v-%v:%v@%v",
+			start.GetLine(), start.GetPosition(), start.GetOffset(),
+			end.GetLine(), end.GetPosition(), end.GetOffset(),
+		)
+	}
+	var statements = document.GetGrammar().GetStatements().AsArray()
+	ass.Equal(t, "1:1@0-1:17@17", formatSpan(statements[0].GetSpan()))
+	var definition = statements[1].GetDefinition()
+	ass.Equal(t, "2:1@18-2:31@50", formatSpan(definition.GetSpan()))
+	var factors = definition.GetExpression().GetAlternatives().AsArray()[0].GetFactors().AsArray()
+	ass.Equal(t, "2:12@29-2:27@46", formatSpan(factors[1].GetSpan()))
+	var predicate = factors[1].GetPredicate()
+	ass.Equal(t, "2:12@29-2:21@40", formatSpan(predicate.GetSpan()))
+	ass.Equal(t, "2:13@30-2:21@40", formatSpan(predicate.GetAssertion().GetGlyph().GetSpan()))
+	ass.Equal(t, "2:21@40-2:27@46", formatSpan(factors[1].GetCardinality().GetSpan()))
+	definition = statements[2].GetDefinition()
+	ass.Equal(t, "3:1@51-5:11@95", formatSpan(definition.GetSpan()))
+	var alternatives = definition.GetExpression().GetAlternatives().AsArray()
+	ass.Equal(t, "4:5@63-4:26@84", formatSpan(alternatives[0].GetSpan()))
+	ass.Equal(t, "1:1@0-7:1@97", formatSpan(document.GetSpan()))
+}
+
+func TestInterpreter(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	var cdcn = cds.InterpreterClass().FromDocument(parser.ParseDocument(string(bytes)))
+
+	// Parse some valid collections.
+	var sources = []string{
+		"[ ](List)\n",
+		"[:](Catalog)",
+		"[1, -2.5, \"three\", 0xff](List)\n",
+		"[\n    \"alpha\": 'a'\n    \"beta\": [true, nil](Set)\n](Catalog)\n",
+	}
+	for _, source := range sources {
+		var node, err = cdcn.ParseSource(source)
+		ass.Nil(t, err)
+		ass.Equal(t, "document", node.GetName())
+		ass.Equal(t, sts.TrimSpace(source), sts.TrimSpace(node.GetText()))
+	}
+
+	// Check the concrete syntax tree of a nested collection.
+	var node, _ = cdcn.ParseSource("[1, [true](Set)](List)")
+	var collection = node.GetChildren().AsArray()[0]
+	ass.Equal(t, "collection", collection.GetName())
+	var values = collection.GetChildren().AsArray()[1]
+	ass.Equal(t, "values", values.GetName())
+	var value = values.GetChildren().AsArray()[2]
+	ass.Equal(t, "[true](Set)", value.GetText())
+	ass.Equal(t, "1:5-1:16", fmt.Sprintf("%v", value.GetSpan()))
+	var token = value.GetChildren().AsArray()[0].GetChildren().AsArray()[0]
+	ass.True(t, token.IsToken())
+	ass.Equal(t, `"["`, token.GetName())
+
+	// Parse a long collection.
+	var items = make([]string, 2000)
+	for index := range items {
+		items[index] = fmt.Sprintf("%v", index+1)
+	}
+	node, _ = cdcn.ParseSource("[" + sts.Join(items, ", ") + "](List)")
+	values = node.GetChildren().AsArray()[0].GetChildren().AsArray()[1]
+	ass.Equal(t, 2*len(items)-1, values.GetChildren().GetSize())
+
+	// Parse some invalid collections.
+	var expected = []string{
+		`1:7: An unexpected "]" token "]" was received, was expecting '"[" | BOOLEAN | COMPLEX | FLOAT | INTEGER | NIL | RUNE | STRING | UNSIGNED' from: $value, $collection`,
+		`1:8: An unexpected Error token "B" was received, was expecting 'TYPE' from: $collection, $context`,
+		`1:14: An unexpected Error token "e" was received, was expecting 'EOF' from: $document`,
+	}
+	sources = []string{
+		"[1, 2,](List)",
+		"[1, 2](Bag)",
+		"[1, 2](List) extra",
+	}
+	for index, source := range sources {
+		var _, err = cdcn.ParseSource(source)
+		ass.Equal(t, expected[index], err.Error())
+	}
+
+	// Parse the CDSN grammars using the CDSN grammar.
+	bytes, _ = osx.ReadFile(grammarsDirectory + "cdsn.cdsn")
+	var cdsn = cds.InterpreterClass().FromDocument(parser.ParseDocument(string(bytes)))
+	var files, _ = osx.ReadDir(grammarsDirectory)
+	for _, file := range files {
+		bytes, _ = osx.ReadFile(grammarsDirectory + file.Name())
+		var _, err = cdsn.ParseSource(string(bytes))
+		ass.Nil(t, err)
+	}
+}
+
+func TestNullableTokens(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var grammars = []string{
+		`$document: NAME EOF
+$NAME: "a" ("b"*)
+`,
+		`$document: NAME EOF
+$NAME: "a" Z
+$Z: "b"*
+`,
+		`$document: NAME EOF
+$NAME: Z "a"
+$Z: "-"?
+`,
+		`$document: NAME EOF
+$NAME: "a" Z{2}
+$Z: "b"?
+`,
+		`$document: item{2} "a" EOF
+$item: "b"?
+`,
+	}
+	for _, grammar := range grammars {
+		var document = parser.ParseDocument(grammar)
+		var interpreter = cds.InterpreterClass().FromDocument(document)
+		var node, err = interpreter.ParseSource("a")
+		ass.Nil(t, err)
+		ass.Equal(t, "a", node.GetText())
+	}
+	var document = parser.ParseDocument(grammars[1])
+	var interpreter = cds.InterpreterClass().FromDocument(document)
+	var node, err = interpreter.ParseSource("abb")
+	ass.Nil(t, err)
+	ass.Equal(t, "abb", node.GetText())
+	_, err = interpreter.ParseSource("ba")
+	ass.NotNil(t, err)
+
+	// Extracting a repeated factor from a token yields a nullable sub-token.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	document = parser.ParseDocument(string(bytes))
+	var grammar = document.GetGrammar()
+	var statements = grammar.GetStatements().AsArray()
+	for _, statement := range statements {
+		var definition = statement.GetDefinition()
+		if definition != nil && definition.GetSymbol() == "$ORDINAL" {
+			grammar.Extract(definition, 1, 2, 2, "$DIGITS")
+		}
+	}
+	interpreter = cds.InterpreterClass().FromDocument(document)
+	_, err = interpreter.ParseSource("[1, 23, -4](List)")
+	ass.Nil(t, err)
+}
+
+func TestGenerator(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var generator = cds.GeneratorClass().Default()
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	var files, err = generator.GeneratePackage("cdcn", parser.ParseDocument(string(bytes)))
+	ass.Nil(t, err)
+	ass.Equal(t, 5, files.GetSize())
+
+	// Make sure the generated package compiles.
+	var fileSet = tok.NewFileSet()
+	var sources []*ast.File
+	var iterator = files.GetIterator()
+	for iterator.HasNext() {
+		var association = iterator.GetNext()
+		var source, err = prs.ParseFile(fileSet, association.GetKey(), association.GetValue(), 0)
+		ass.Nil(t, err)
+		sources = append(sources, source)
+	}
+	var configuration = typ.Config{Importer: imp.ForCompiler(fileSet, "source", nil)}
+	_, err = configuration.Check("cdcn", fileSet, sources, nil)
+	ass.Nil(t, err)
+	var source = files.GetValue("parser.go")
+	ass.True(t, sts.Contains(source, "func (v *parser_) parseCollection() (NodeLike, bool) {"))
+	ass.True(t, sts.Contains(source, "return v.matchToken(`\"[\"`, children) &&"))
+
+	// Make sure a generated parser accepts nullable repetitions.
+	files, err = generator.GeneratePackage("sample", parser.ParseDocument(`$document: item{2} "a" EOF
+$item: "b"?
+`))
+	ass.Nil(t, err)
+	var directory = t.TempDir()
+	osx.Mkdir(pth.Join(directory, "sample"), 0755)
+	iterator = files.GetIterator()
+	for iterator.HasNext() {
+		var association = iterator.GetNext()
+		var filename = pth.Join(directory, "sample", association.GetKey())
+		ass.Nil(t, osx.WriteFile(filename, []byte(association.GetValue()), 0644))
+	}
+	osx.WriteFile(pth.Join(directory, "go.mod"), []byte("module example\n\ngo 1.21\n"), 0644)
+	osx.WriteFile(pth.Join(directory, "main.go"), []byte(`package main
+
+import (
+	fmt "fmt"
+	sam "example/sample"
+	osx "os"
+)
+
+func main() {
+	for _, sentence := range osx.Args[1:] {
+		var _, err = sam.ParserClass().Default().ParseSource(sentence)
+		fmt.Println(err == nil)
+	}
+}
+`), 0644)
+	var command = exe.Command("go", "run", ".", "a", "ba", "bba", "bbba")
+	command.Dir = directory
+	var output, _ = command.CombinedOutput()
+	ass.Equal(t, "true\ntrue\ntrue\nfalse\n", string(output))
+
+	// Make sure invalid package names are rejected.
+	_, err = generator.GeneratePackage("Bad-Name", parser.ParseDocument(string(bytes)))
+	ass.Equal(t, `The package Bad-Name could not be generated: The package name "Bad-Name" is not a valid Go package name.`, err.Error())
+}
+
+func TestCompiler(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdsn.cdsn")
+	var document = parser.ParseDocument(string(bytes))
+	var compiler = cds.CompilerClass().FromDocument(document)
+
+	// Compile the token definitions that the scanner hard-codes.
+	var expected = map[string]string{
+		"$CHARACTER": `'[^\p{Cc}]'`,
+		"$COMMENT":   `!>(?s:.)*?<!`,
+		"$NAME":      `(?:\p{Ll}|\p{Lu})(?:_?(?:(?:\p{Ll}|\p{Lu})|\p{Nd}))*`,
+		"$NUMBER":    `\p{Nd}+`,
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil {
+			continue
+		}
+		var pattern, ok = expected[definition.GetSymbol()]
+		if ok {
+			var compiled, err = compiler.CompileDefinition(definition)
+			ass.Nil(t, err)
+			ass.Equal(t, pattern, compiled)
+		}
+	}
+
+	// Make sure the compiled patterns match like the scanner does.
+	var definition = parser.ParseDocument(`$LITERAL: '"' (ESCAPE | ~('"' | CONTROL))+ '"'
+`).GetGrammar().GetStatements().AsArray()[0].GetDefinition()
+	var pattern, _ = compiler.CompileDefinition(definition)
+	var matcher = reg.MustCompile(`^(?:` + pattern + `)`)
+	ass.Equal(t, `"a\"bé"`, matcher.FindString(`"a\"bé" + "c"`))
+	ass.Equal(t, "", matcher.FindString(`""`))
+
+	// Make sure constructs that cannot be compiled are reported.
+	var errors = map[string]string{
+		`$A: "a" EOF
+`: `The token definition for $A cannot be compiled: The intrinsic EOF cannot be matched within a token.`,
+		`$B: ~"ab"
+`: `The token definition for $B cannot be compiled: The literal "ab" cannot be inverted.`,
+		`$C: 'c'{1001}
+`: `The token definition for $C cannot be compiled: A repetition count cannot be larger than 1000.`,
+		`$D: "d" D?
+`: `The token definition for $D cannot be compiled: The token D is recursive.`,
+	}
+	for source, message := range errors {
+		document = parser.ParseDocument(source)
+		definition = document.GetGrammar().GetStatements().AsArray()[0].GetDefinition()
+		var _, err = cds.CompilerClass().FromDocument(document).CompileDefinition(definition)
+		ass.Equal(t, message, err.Error())
+	}
+}
+
+func TestReferenceDiagnostics(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var validator = cds.ValidatorClass().Default()
+
+	// The parser rejects undefined names so remove a definition afterwards.
+	var document = parser.ParseDocument(`$document: list EOF
+$list: "[" item* "]"
+$item: NUMBER
+$orphan: island
+$island: "(" orphan ")" | NUMBER
+$NUMBER: DIGIT+
+$SPARE: "spare"
+`)
+	var statements = col.ListClass[cds.StatementLike]().Empty()
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		if statement.GetDefinition().GetSymbol() != "$item" {
+			statements.AppendValue(statement)
+		}
+	}
+	document.SetGrammar(cds.GrammarClass().FromStatements(statements))
+
+	var diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, 5, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetUndefined(), diagnostics[0].GetKind())
+	ass.Equal(t, cds.DiagnosticClass().GetError(), diagnostics[0].GetSeverity())
+	ass.Equal(t, "$list", diagnostics[0].GetSymbol())
+	ass.Equal(t, "The grammar is missing a definition for name: item", diagnostics[0].GetMessage())
+	ass.Equal(t, 2, diagnostics[0].GetLine())
+	ass.Equal(t, 12, diagnostics[0].GetPosition())
+	ass.Equal(t, cds.DiagnosticClass().GetUnreachable(), diagnostics[1].GetKind())
+	ass.Equal(t, "$orphan", diagnostics[1].GetSymbol())
+	ass.Equal(t, cds.DiagnosticClass().GetUnreachable(), diagnostics[2].GetKind())
+	ass.Equal(t, "$island", diagnostics[2].GetSymbol())
+	ass.Equal(t, "The definition for $NUMBER cannot be reached from $document.", diagnostics[3].GetMessage())
+	ass.Equal(t, cds.DiagnosticClass().GetUnused(), diagnostics[4].GetKind())
+	ass.Equal(t, cds.DiagnosticClass().GetWarning(), diagnostics[4].GetSeverity())
+	ass.Equal(t, "The definition for $SPARE is never used.", diagnostics[4].GetMessage())
+
+	// Warnings alone do not cause validation to fail.
+	validator.ValidateDocument(parser.ParseDocument(`$document: "a" EOF
+$unused: "b"
+`))
+}
+
+func TestRecursiveTokens(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var validator = cds.ValidatorClass().Default()
+	var document = parser.ParseDocument(`$A: B
+$B: "x" A | C
+$C: "c" C?
+$D: A
+`)
+	var diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, 2, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetRecursive(), diagnostics[0].GetKind())
+	ass.Equal(t, "$A", diagnostics[0].GetSymbol())
+	ass.Equal(t, "A token definition cannot be recursive: $A → $B → $A", diagnostics[0].GetMessage())
+	ass.Equal(t, "$C", diagnostics[1].GetSymbol())
+	ass.Equal(t, "A token definition cannot be recursive: $C → $C", diagnostics[1].GetMessage())
+
+	defer func() {
+		if e := recover(); e != nil {
+			ass.Equal(
+				t,
+				"The definition for $A is invalid:\nA token definition cannot be recursive: $A → $B → $A\n",
+				e,
+			)
+		} else {
+			ass.Fail(t, "Test should result in recovered panic.")
+		}
+	}()
+	validator.ValidateDocument(document)
+}
+
+func TestLeftRecursion(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`$document: expression EOF
+$expression: expression "+" term | term
+$term: prefix? factor
+$prefix: "-"*
+$factor: NUMBER | "(" expression ")" | list
+$list: option* "[" factor "]"
+$option: list ","
+$NUMBER: DIGIT+
+`)
+	var analyzer = cds.AnalyzerClass().FromDocument(document)
+	ass.True(t, analyzer.IsNullable("$prefix"))
+	ass.False(t, analyzer.IsNullable("$term"))
+	ass.Nil(t, analyzer.GetLeftRecursion("$term"))
+	ass.Equal(t, []string{"$expression", "$expression"}, analyzer.GetLeftRecursion("$expression").AsArray())
+	ass.Equal(t, []string{"$list", "$option", "$list"}, analyzer.GetLeftRecursion("$list").AsArray())
+
+	var validator = cds.ValidatorClass().Default()
+	var diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, 3, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetLeftRecursive(), diagnostics[0].GetKind())
+	ass.Equal(t, "$expression", diagnostics[0].GetSymbol())
+	ass.Equal(t, "A rule definition cannot be left recursive: $expression → $expression", diagnostics[0].GetMessage())
+	ass.Equal(t, 2, diagnostics[0].GetLine())
+	ass.Equal(t, "$list", diagnostics[1].GetSymbol())
+	ass.Equal(t, "$option", diagnostics[2].GetSymbol())
+	ass.Equal(t, "A rule definition cannot be left recursive: $option → $list → $option", diagnostics[2].GetMessage())
+	ass.Equal(t, cds.DiagnosticClass().GetWarning(), diagnostics[0].GetSeverity())
+	validator.ValidateDocument(document)
+
+	// Only a strict validator rejects left recursive rules.
+	validator = cds.ValidatorClass().Strict()
+	diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, cds.DiagnosticClass().GetError(), diagnostics[0].GetSeverity())
+	defer func() {
+		if e := recover(); e != nil {
+			ass.Contains(t, e, "A rule definition cannot be left recursive: $expression → $expression")
+		} else {
+			ass.Fail(t, "Test should result in recovered panic.")
+		}
+	}()
+	validator.ValidateDocument(document)
+}
+
+func TestFirstFollowSets(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`$document: statement* EOF
+$statement: assignment | call
+$assignment: NAME "=" value
+$call: NAME "(" value? ")"
+$value: sign? NUMBER
+$sign: "-"*
+$NAME: LOWER+
+$NUMBER: DIGIT+
+`)
+	var analyzer = cds.AnalyzerClass().FromDocument(document)
+	ass.Equal(t, []string{"NAME"}, analyzer.GetFirstSet("$statement").AsArray())
+	ass.Equal(t, []string{"\"-\"", "NUMBER"}, analyzer.GetFirstSet("$value").AsArray())
+	ass.Equal(t, []string{"EOF", "NAME"}, analyzer.GetFollowSet("$statement").AsArray())
+	ass.Equal(t, []string{"\")\"", "EOF", "NAME"}, analyzer.GetFollowSet("$value").AsArray())
+	ass.Equal(t, []string{"NUMBER"}, analyzer.GetFollowSet("$sign").AsArray())
+
+	var conflicts = analyzer.GetConflicts().AsArray()
+	ass.Equal(t, 1, len(conflicts))
+	ass.Equal(t, cds.DiagnosticClass().GetConflict(), conflicts[0].GetKind())
+	ass.Equal(t, cds.DiagnosticClass().GetWarning(), conflicts[0].GetSeverity())
+	ass.Equal(t, "$statement", conflicts[0].GetSymbol())
+	ass.Equal(t, "The alternatives 1 and 2 both begin with: NAME", conflicts[0].GetMessage())
+	ass.Equal(t, 2, conflicts[0].GetLine())
+
+	var validator = cds.ValidatorClass().Default()
+	ass.Equal(t, 0, validator.DiagnoseDocument(document).GetSize())
+	validator = cds.ValidatorClass().Strict()
+	var diagnostics = validator.DiagnoseDocument(document).AsArray()
+	ass.Equal(t, 1, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetError(), diagnostics[0].GetSeverity())
+	ass.Equal(t, cds.DiagnosticClass().GetConflict(), diagnostics[0].GetKind())
+	defer func() {
+		if e := recover(); e != nil {
+			ass.Contains(t, e, "The alternatives 1 and 2 both begin with: NAME")
+		} else {
+			ass.Fail(t, "Test should result in recovered panic.")
+		}
+	}()
+	validator.ValidateDocument(document)
+}
+
+type nameCollector struct {
+	cds.VisitorLike
+	depths []int
+	names  []string
+	tokens int
+}
+
+func (v *nameCollector) PreDefinition(definition cds.DefinitionLike, walker cds.WalkerLike) bool {
+	// Skip the token definitions.
+	if definition.GetSymbol() < "$a" {
+		v.tokens++
+		return false
+	}
+	return true
+}
+
+func (v *nameCollector) PreElement(element cds.ElementLike, walker cds.WalkerLike) bool {
+	if len(element.GetName()) > 0 {
+		var symbol = walker.GetDefinition().GetSymbol()
+		v.names = append(v.names, symbol+"→"+element.GetName())
+		v.depths = append(v.depths, walker.GetDepth())
+	}
+	return true
+}
+
+func TestWalker(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`$document: list EOF
+$list: "[" (item ("," item)*)? "]"
+$item: NUMBER | list
+$NUMBER: DIGIT+
+`)
+	var collector = &nameCollector{
+		VisitorLike: cds.VisitorClass().Default(),
+	}
+	var walker = cds.WalkerClass().FromVisitor(collector)
+	walker.WalkDocument(document)
+	ass.Equal(t, 1, collector.tokens)
+	ass.Equal(t, []string{
+		"$document→list",
+		"$list→item",
+		"$list→item",
+		"$item→NUMBER",
+		"$item→list",
+	}, collector.names)
+	ass.Equal(t, []int{9, 15, 21, 9, 9}, collector.depths)
+	ass.Nil(t, walker.GetDefinition())
+	ass.Equal(t, 0, walker.GetDepth())
+
+	collector.names = nil
+	collector.depths = nil
+	var definition = document.GetGrammar().GetStatements().AsArray()[2].GetDefinition()
+	walker.WalkDefinition(definition)
+	ass.Equal(t, []string{"$item→NUMBER", "$item→list"}, collector.names)
+	ass.Equal(t, []int{6, 6}, collector.depths)
+}
+
+const exporterGrammar = `!>
+    EXAMPLE
+<!
+$document: list EOF
+$list: "[" items? "]"
+$items:
+    item ("," item)*
+    EOL (item EOL)+
+
+$item: NAME | NUMBER{2..3} | ~"]"
+$NAME: 'a'..'z' ('_'? LOWER)*
+$NUMBER: DIGIT{1..} | '#' ~('0' | '1'){2}
+`
+
+func TestExporter(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(exporterGrammar)
+
+	var exporter = cds.ExporterClass().FromNotation(cds.ExporterClass().GetW3C())
+	ass.Equal(t, `/*
+    EXAMPLE
+*/
+document ::= list /* EOF */
+list ::= "[" items? "]"
+items ::= item ("," item)*
+    | EOL (item EOL)+
+item ::= NAME | NUMBER NUMBER NUMBER? | [^#x5D]
+NAME ::= [a-z] ("_"? LOWER)*
+NUMBER ::= DIGIT+ | "#" [^01] [^01]
+
+/*
+    INTRINSIC DEFINITIONS
+    The following definitions describe the intrinsics used by the grammar.
+*/
+DIGIT ::= [0-9]
+EOL ::= #xA
+LOWER ::= [a-z]
+`, exporter.ExportDocument(document))
+	var diagnostics = exporter.GetDiagnostics().AsArray()
+	ass.Equal(t, 3, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetLossy(), diagnostics[0].GetKind())
+	ass.Equal(t, "$document", diagnostics[0].GetSymbol())
+	ass.Equal(t, "There is no equivalent for the intrinsic EOF.", diagnostics[0].GetMessage())
+	ass.Equal(t, "The intrinsic LOWER only matches ASCII characters.", diagnostics[1].GetMessage())
+	ass.Equal(t, "The intrinsic DIGIT only matches ASCII characters.", diagnostics[2].GetMessage())
+
+	exporter = cds.ExporterClass().FromNotation(cds.ExporterClass().GetISO())
+	ass.Equal(t, `(*
+    EXAMPLE
+*)
+document = list, EOF ;
+list = "[", [items], "]" ;
+items = item, {",", item}
+    | EOL, (item, EOL), {item, EOL} ;
+item = NAME | 2 * NUMBER, [NUMBER] | (ANY - "]") ;
+NAME = ("a" | "b" | "c" | "d" | "e" | "f" | "g" | "h" | "i" | "j" | "k" | "l" | "m" | "n" | "o" | "p" | "q" | "r" | "s" | "t" | "u" | "v" | "w" | "x" | "y" | "z"), {["_"], LOWER} ;
+NUMBER = DIGIT, {DIGIT} | "#", 2 * (ANY - ("0" | "1")) ;
+
+(*
+    INTRINSIC DEFINITIONS
+    The following definitions describe the intrinsics used by the grammar.
+*)
+ANY = ? any character ? ;
+DIGIT = ? any decimal digit ? ;
+EOF = ? the end of the input ? ;
+EOL = ? the end of line character ? ;
+LOWER = ? any lower case letter ? ;
+`, exporter.ExportDocument(document))
+	ass.Equal(t, 5, exporter.GetDiagnostics().GetSize())
+
+	// Make sure large repetition counts keep their optionality when relaxed.
+	document = parser.ParseDocument(`$document: "a"{0..20} "b"{17..20} EOF
+`)
+	exporter = cds.ExporterClass().FromNotation(cds.ExporterClass().GetW3C())
+	ass.True(t, sts.Contains(exporter.ExportDocument(document), `document ::= "a"* "b"+ /* EOF */`))
+
+	// Make sure a factor that is repeated zero times is dropped.
+	document = parser.ParseDocument(`$document: "a"{0} "b" EOF
+`)
+	exporter = cds.ExporterClass().FromNotation(cds.ExporterClass().GetW3C())
+	ass.True(t, sts.Contains(exporter.ExportDocument(document), `document ::= "b" /* EOF */`))
+	diagnostics = exporter.GetDiagnostics().AsArray()
+	ass.Equal(t, "A factor that is repeated zero times has been dropped.", diagnostics[0].GetMessage())
+	exporter = cds.ExporterClass().FromNotation(cds.ExporterClass().GetISO())
+	ass.True(t, sts.Contains(exporter.ExportDocument(document), `document = "b", EOF ;`))
+}
+
+func TestANTLR(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(exporterGrammar + `$grammar: LETTER{3} 'x'..'z' ! Reserved.
+$LETTER: 'a'..'f'
+$QUOTED: "'" ANY* "'" | "'" ~(LETTER | DIGIT | '-') "'"
+`)
+	var generator = cds.GeneratorClass().Default()
+	var grammar, err = generator.GenerateANTLR("Example", document)
+	ass.Equal(t, nil, err)
+	ass.Equal(t, `// This grammar was generated from a CDSN grammar.  The start rule is document.
+grammar Example;
+
+/*
+    EXAMPLE
+*/
+
+document
+    : list EOF
+    ;
+
+list
+    : '[' items? ']'
+    ;
+
+items
+    : item (',' item)*
+    | EOL (item EOL)+
+    ;
+
+item
+    : NAME
+    | NUMBER NUMBER NUMBER?
+    | ~']'
+    ;
+
+NAME
+    : [a-z] ('_'? LOWER)*
+    ;
+
+NUMBER
+    : DIGIT+
+    | '#' ~[01] ~[01]
+    ;
+
+grammar_
+    : LETTER LETTER LETTER RANGE_1_  // Reserved.
+    ;
+
+LETTER
+    : [a-f]
+    ;
+
+fragment QUOTED
+    : '\'' ANY*? '\''
+    | '\'' ~[a-f\p{Nd}\-] '\''
+    ;
+
+RANGE_1_
+    : [x-z]
+    ;
+
+fragment ANY
+    : .
+    ;
+
+fragment DIGIT
+    : [\p{Nd}]
+    ;
+
+EOL
+    : '\n'
+    ;
+
+fragment LOWER
+    : [\p{Ll}]
+    ;
+
+// Spaces between the tokens of a rule are ignored.
+SPACES_
+    : ' '+ -> skip
+    ;
+`, grammar)
+
+	// A terminal that matches a single space would hide any skipped spaces.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	grammar, err = generator.GenerateANTLR("CDCN", parser.ParseDocument(string(bytes)))
+	ass.Equal(t, nil, err)
+	ass.NotContains(t, grammar, "-> skip")
+	ass.True(t, sts.HasSuffix(grammar, `
+// Spaces between the tokens of a rule are NOT ignored since the
+// terminal " " matches a single space.
+`))
+
+	grammar, err = generator.GenerateANTLR("2nd", document)
+	ass.Equal(t, "", grammar)
+	ass.Equal(t, `The grammar 2nd could not be generated: The grammar name "2nd" is not a valid ANTLR grammar name.`, err.Error())
+
+	// A factor that is repeated zero times is refused.
+	_, err = generator.GenerateANTLR("Zero", parser.ParseDocument(`$document: "a"{0} "b" EOF
+`))
+	ass.Equal(t, "The grammar Zero could not be generated: A factor cannot be repeated zero times.", err.Error())
+}
+
+func TestDiagrammer(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`$list: "[" NAME* ~']' "]"
+$NAME: 'a'..'z'{1..3}
+`)
+	var definition = document.GetGrammar().GetStatements().AsArray()[0].GetDefinition()
+	var diagrammer = cds.DiagrammerClass().FromLinkFormat("tokens.svg#%v")
+	ass.Equal(t, "tokens.svg#%v", diagrammer.GetLinkFormat())
+	ass.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="354" height="108" viewBox="0 0 354 108">
+<style>
+path { fill: none; stroke: black; stroke-width: 2; }
+rect { fill: #eef; stroke: black; stroke-width: 2; }
+rect.inversion { fill: none; stroke-dasharray: 4 2; stroke-width: 1; }
+text { font: 13px monospace; text-anchor: middle; }
+text.label { font-size: 11px; }
+text.marker, text.symbol { font-weight: bold; text-anchor: start; }
+a text { fill: blue; }
+</style>
+<g class="definition" id="list" transform="translate(0 0)">
+<text class="symbol" x="10" y="24">$list</text>
+<path d="M10 46 v20 m10 -20 v20 m0 -10 h10"/>
+<rect class="terminal" x="30" y="45" width="44" height="22" rx="10"/>
+<text x="52" y="60">&quot;[&quot;</text>
+<path d="M74 56 h10"/>
+<path d="M84 56 H104"/>
+<path d="M104 56 H196"/>
+<path d="M84 56 a10 10 0 0 1 10 10 v1 a10 10 0 0 0 10 10"/>
+<path d="M176 77 h0 a10 10 0 0 0 10 -10 v-1 a10 10 0 0 1 10 -10"/>
+<path d="M104 77 H114"/>
+<a href="tokens.svg#NAME">
+<rect class="nonterminal" x="114" y="66" width="52" height="22" rx="0"/>
+<text x="140" y="81">NAME</text>
+</a>
+<path d="M166 77 H176"/>
+<path d="M166 77 a10 10 0 0 1 10 10 v1 a10 10 0 0 1 -10 10 h-52 a10 10 0 0 1 -10 -10 v-1 a10 10 0 0 1 10 -10"/>
+<path d="M196 56 h10"/>
+<rect class="inversion" x="206" y="30" width="64" height="42"/>
+<text class="marker" x="210" y="42">~</text>
+<path d="M206 56 h10"/>
+<rect class="terminal" x="216" y="45" width="44" height="22" rx="10"/>
+<text x="238" y="60">']'</text>
+<path d="M260 56 h10"/>
+<path d="M270 56 h10"/>
+<rect class="terminal" x="280" y="45" width="44" height="22" rx="10"/>
+<text x="302" y="60">&quot;]&quot;</text>
+<path d="M324 56 h20 m-10 -10 v20 m10 -20 v20"/>
+</g>
+</svg>
+`, diagrammer.DiagramDefinition(definition))
+
+	diagrammer = cds.DiagrammerClass().Default()
+	var svg = diagrammer.DiagramDocument(document)
+	ass.Equal(t, 2, sts.Count(svg, `<g class="definition"`))
+	ass.True(t, sts.Contains(svg, `<a href="#NAME">`))
+	ass.True(t, sts.Contains(svg, `<g class="definition" id="NAME" transform="translate(0 108)">`))
+	ass.True(t, sts.Contains(svg, `<text class="label" x="`))
+	ass.True(t, sts.Contains(svg, `>1 to 3</text>`))
+
+	defer func() {
+		if e := recover(); e != nil {
+			ass.Equal(t, "The link format must contain a %v placeholder: #", e)
+		} else {
+			ass.Fail(t, "Test should result in recovered panic.")
+		}
+	}()
+	cds.DiagrammerClass().FromLinkFormat("#")
+}
+
+func TestPublisher(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`!>
+    LISTS
+    A list contains
+    names.
+
+    The names:
+     * are lower case.
+     * may contain digits.
+<!
+$list: "[" NAME* "]"  ! The list may be empty.
+$NAME: LOWER (LOWER | DIGIT){0..7}
+$other: "<" ~NAME ">"
+`)
+	var publisher = cds.PublisherClass().Default()
+	var html = publisher.PublishDocument(document)
+	ass.True(t, sts.HasPrefix(html, "<!DOCTYPE html>\n"))
+	ass.True(t, sts.Contains(html, "<title>LISTS</title>\n"))
+	ass.True(t, sts.Contains(html, `<section class="comment">
+<h2>LISTS</h2>
+<p>A list contains names.</p>
+<p>The names:</p>
+<ul>
+<li>are lower case.</li>
+<li>may contain digits.</li>
+</ul>
+</section>
+`))
+	ass.True(t, sts.Contains(html, `<section class="definition" id="list">
+<h3><a href="#list">$list</a></h3>
+<ul class="alternatives">
+<li><code>&quot;[&quot; <a href="#NAME">NAME</a>* &quot;]&quot;</code> <span class="note">The list may be empty.</span></li>
+</ul>
+</section>
+`))
+	ass.True(t, sts.Contains(html, `<li><code>LOWER (LOWER | DIGIT){0..7}</code></li>`))
+	ass.True(t, sts.Contains(html, `<li><code>&quot;&lt;&quot; ~<a href="#NAME">NAME</a> &quot;&gt;&quot;</code></li>`))
+	ass.True(t, sts.Contains(html, `<table>
+<tr><th>Name</th><th>Used By</th></tr>
+<tr><td><a href="#NAME">NAME</a></td><td><a href="#list">list</a>, <a href="#other">other</a></td></tr>
+<tr><td><a href="#list">list</a></td><td><em>unused</em></td></tr>
+<tr><td><a href="#other">other</a></td><td><em>unused</em></td></tr>
+</table>
+`))
+	ass.True(t, sts.HasSuffix(html, "</body>\n</html>\n"))
+}
+
+func TestSerializer(t *tes.T) {
+	var files, err = osx.ReadDir(grammarsDirectory)
+	if err != nil {
+		panic("Could not find the " + grammarsDirectory + " directory.")
+	}
+	var parser = cds.ParserClass().Default()
+	var formatter = cds.FormatterClass().Default()
+	var formats = []string{
+		cds.SerializerClass().GetJSON(),
+		cds.SerializerClass().GetYAML(),
+	}
+	for _, file := range files {
+		var bytes, _ = osx.ReadFile(grammarsDirectory + file.Name())
+		var expected = string(bytes)
+		var document = parser.ParseDocument(expected)
+		for _, format := range formats {
+			var serializer = cds.SerializerClass().FromFormat(format)
+			var source = serializer.SerializeDocument(document)
+			var copy, err = serializer.DeserializeDocument(source)
+			ass.Nil(t, err)
+			ass.Equal(t, expected, formatter.FormatDocument(copy))
+			ass.Equal(t, source, serializer.SerializeDocument(copy))
+		}
+	}
+
+	var serializer = cds.SerializerClass().FromFormat(cds.SerializerClass().GetJSON())
+	ass.Equal(t, "JSON", serializer.GetFormat())
+	var document = parser.ParseDocument(`$NAME: ~'"' 'a'..'z'{1..3}
+`)
+	ass.Equal(t, `{
+    "grammar": {
+        "statements": [
+            {
+                "definition": {
+                    "expression": {
+                        "alternatives": [
+                            {
+                                "factors": [
+                                    {
+                                        "predicate": {
+                                            "assertion": {
+                                                "glyph": {
+                                                    "first": "'\"'"
+                                                }
+                                            },
+                                            "inverted": true
+                                        }
+                                    },
+                                    {
+                                        "cardinality": {
+                                            "constraint": {
+                                                "first": "1",
+                                                "last": "3"
+                                            }
+                                        },
+                                        "predicate": {
+                                            "assertion": {
+                                                "glyph": {
+                                                    "first": "'a'",
+                                                    "last": "'z'"
+                                                }
+                                            }
+                                        }
+                                    }
+                                ]
+                            }
+                        ]
+                    },
+                    "symbol": "$NAME"
+                }
+            }
+        ]
+    }
+}
+`, serializer.SerializeDocument(document))
+
+	_, err = serializer.DeserializeDocument(`{"grammar": {"statements": [{"comment": "!><!", "symbol": "$x"}]}}`)
+	ass.Equal(t, `The JSON document cannot be deserialized: json: unknown field "symbol"`, err.Error())
+	_, err = serializer.DeserializeDocument(`{"grammar": {"statements": [{"definition": {"symbol": "$x"}}]}}`)
+	ass.Equal(t, "The JSON document cannot be deserialized: An expression requires at least one alternative.", err.Error())
+	serializer = cds.SerializerClass().FromFormat(cds.SerializerClass().GetYAML())
+	_, err = serializer.DeserializeDocument("grammar:\n    statements:\n        - comment: \"!><!\"\n          definition: {}\n")
+	ass.Equal(t, "The YAML document cannot be deserialized: A statement requires exactly one comment or definition.", err.Error())
+}
+
+const abnfGrammar = `; A list of numbers.
+list = "(" [item *("," item)] ")" ; The list may be empty.
+item = list / number / %s"Nil" / "nil"
+item =/ <anything else>
+number = 1*3DIGIT [%x2E 1*DIGIT] / %d13.10
+`
+
+func TestImporter(t *tes.T) {
+	var importer = cds.ImporterClass().FromNotation(cds.ImporterClass().GetABNF())
+	var document, err = importer.ImportSource(abnfGrammar)
+	ass.Nil(t, err)
+	var formatter = cds.FormatterClass().Default()
+	var source = formatter.FormatDocument(document)
+	ass.Equal(t, `!>
+    A list of numbers.
+<!
+$list: "(" (item ("," item)*)? ")"  ! The list may be empty.
+$item: list | NUMBER | "Nil" | "nil" | "<anything else>"
+$NUMBER: ABNF_DIGIT{1..3} ('.' ABNF_DIGIT+)? | "\r\n"
+
+!>
+    CORE RULES
+    The following core rules are defined in appendix B of RFC 5234.
+<!
+$ABNF_DIGIT: '0'..'9'
+`, source)
+	var parser = cds.ParserClass().Default()
+	ass.Equal(t, source, formatter.FormatDocument(parser.ParseDocument(source)))
+
+	var diagnostics = importer.GetDiagnostics().AsArray()
+	ass.Equal(t, 5, len(diagnostics))
+	ass.Equal(t, cds.DiagnosticClass().GetLossy(), diagnostics[0].GetKind())
+	ass.Equal(t, "$item", diagnostics[0].GetSymbol())
+	ass.Equal(t, "The string \"nil\" is case insensitive but only this case is matched.", diagnostics[0].GetMessage())
+	ass.Equal(t, 3, diagnostics[0].GetLine())
+	ass.Equal(t, 34, diagnostics[0].GetPosition())
+	ass.Equal(t, "The prose <anything else> must be replaced manually.", diagnostics[1].GetMessage())
+	ass.Equal(t, "The incremental alternatives for item were appended to its definition.", diagnostics[2].GetMessage())
+	ass.Equal(t, "The rule list is recursive, or uses a recursive rule, so spaces between its tokens are ignored.", diagnostics[3].GetMessage())
+	ass.Equal(t, "$list", diagnostics[3].GetSymbol())
+
+	// Different ABNF names that map onto the same CDSN name are kept apart.
+	document, err = importer.ImportSource("foo = foo- %s\"b\"\nfoo- = %s\"a\"\n")
+	ass.Nil(t, err)
+	ass.Equal(t, `$FOO: FOO_2 "b"
+$FOO_2: "a"
+`, formatter.FormatDocument(document))
+	diagnostics = importer.GetDiagnostics().AsArray()
+	ass.Equal(t, 1, len(diagnostics))
+	ass.Equal(t, "$FOO_2", diagnostics[0].GetSymbol())
+	ass.Equal(t, "The rule foo- was renamed FOO_2 since the name FOO is already used.", diagnostics[0].GetMessage())
+
+	// A grammar made only of terminals becomes token definitions.
+	document, err = importer.ImportSource("color = \"#\" 6HEXDIG\n")
+	ass.Nil(t, err)
+	ass.Equal(t, `$COLOR: "#" HEXDIG{6}
+
+!>
+    CORE RULES
+    The following core rules are defined in appendix B of RFC 5234.
+<!
+$HEXDIG: ABNF_DIGIT | 'A'..'F' | 'a'..'f'
+$ABNF_DIGIT: '0'..'9'
+`, formatter.FormatDocument(document))
+	ass.Equal(t, 0, importer.GetDiagnostics().GetSize())
+	var interpreter = cds.InterpreterClass().FromDocument(parser.ParseDocument(`$document: COLOR EOF
+` + formatter.FormatDocument(document)))
+	_, err = interpreter.ParseSource("#00ffAA")
+	ass.Nil(t, err)
+
+	_, err = importer.ImportSource("list = \"(\" item \")\"\n")
+	ass.Equal(t, "The ABNF grammar cannot be imported: The rule list uses the undefined rule item.", err.Error())
+	_, err = importer.ImportSource("list = \"(\" 1*\n")
+	ass.Equal(t, "The ABNF grammar cannot be imported: Expected an element at line 1, position 14.", err.Error())
+}
+
+func TestSampler(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+
+	// Sample some collections that conform to the grammar.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	var cdcn = parser.ParseDocument(string(bytes))
+	var sampler = cds.SamplerClass().FromDocumentAndSeed(cdcn, 42)
+	ass.Equal(t, int64(42), sampler.GetSeed())
+	var interpreter = cds.InterpreterClass().FromDocument(cdcn)
+	for i := 0; i < 100; i++ {
+		var sentence, err = sampler.SampleSentence("$document")
+		ass.Nil(t, err)
+		_, err = interpreter.ParseSource(sentence)
+		ass.Nil(t, err, sentence)
+	}
+
+	// A note runs to the end of the line so nothing can follow it on the line.
+	bytes, _ = osx.ReadFile(grammarsDirectory + "cdsn.cdsn")
+	var cdsn = parser.ParseDocument(string(bytes))
+	interpreter = cds.InterpreterClass().FromDocument(cdsn)
+	for _, seed := range []int64{12, 16, 33} {
+		sampler = cds.SamplerClass().FromDocumentAndSeed(cdsn, seed)
+		for i := 0; i < 10; i++ {
+			var sentence, err = sampler.SampleSentence("$document")
+			ass.Nil(t, err)
+			_, err = interpreter.ParseSource(sentence)
+			ass.Nil(t, err, sentence)
+		}
+	}
+
+	// The same seed always produces the same sentences.
+	var document = parser.ParseDocument(`$document: word+ EOF
+$word: LETTER{2..} | NUMBER | ~("x" | "y")
+$LETTER: 'a'..'z' | ~LOWER
+$NUMBER: DIGIT+ ("." DIGIT{1..3})?
+`)
+	var first = cds.SamplerClass().FromDocument(document)
+	var second = cds.SamplerClass().FromDocument(document)
+	for i := 0; i < 10; i++ {
+		var expected, _ = first.SampleSentence("$document")
+		var actual, _ = second.SampleSentence("$document")
+		ass.Equal(t, expected, actual)
+	}
+
+	// Recursive definitions terminate beyond the maximum depth.
+	document = parser.ParseDocument(`$document: list EOF
+$list: "(" list* ")" | "x"
+`)
+	sampler = cds.SamplerClass().FromDocument(document)
+	sampler.SetMaximumDepth(2)
+	sampler.SetMaximumRepetitions(5)
+	ass.Equal(t, 2, sampler.GetMaximumDepth())
+	ass.Equal(t, 5, sampler.GetMaximumRepetitions())
+	interpreter = cds.InterpreterClass().FromDocument(document)
+	for i := 0; i < 20; i++ {
+		var sentence, _ = sampler.SampleSentence("$document")
+		ass.LessOrEqual(t, sts.Count(sentence, "("), 1+5+25)
+		var _, err = interpreter.ParseSource(sentence)
+		ass.Nil(t, err)
+	}
+
+	// Report symbols that cannot be sampled.
+	var _, err = sampler.SampleSentence("$missing")
+	ass.Equal(t, "A sentence for $missing could not be sampled: The symbol is not defined: $missing", err.Error())
+	document = parser.ParseDocument("$document: loop EOF\n$loop: \"(\" loop \")\"\n")
+	_, err = cds.SamplerClass().FromDocument(document).SampleSentence("$document")
+	ass.Equal(t, "A sentence for $document could not be sampled: The definition can never be completed without recursing forever.", err.Error())
+}
+
+func TestExemplifier(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var document = parser.ParseDocument(`$document: word{1..2} EOF
+$word: LETTER+ | NUMBER
+$LETTER: 'a'..'c'
+$NUMBER: DIGIT+ ("." DIGIT{2..3})?
+`)
+	var exemplifier = cds.ExemplifierClass().FromDocument(document)
+	var examples, err = exemplifier.GenerateExamples("$document")
+	ass.Nil(t, err)
+
+	// Examples that repeat an earlier sentence, or that are invalid by
+	// construction but still accepted (e.g. "000" for "." removed), are dropped.
+	var expected = []string{
+		`Valid: $document: the shortest sentence: "a"`,
+		`Valid: $document: word{1..2} with a count of 2: "a a"`,
+		`Invalid: $document: word{1..2} with a count of 0: ""`,
+		`Valid: $word: alternative 2 of 2: "0"`,
+		`Valid: $LETTER: "c" for 'a'..'c': "c"`,
+		`Invalid: $LETTER: "` + "`" + `" for 'a'..'c': "` + "`" + `"`,
+		`Invalid: $LETTER: "d" for 'a'..'c': "d"`,
+		`Valid: $NUMBER: DIGIT+ with a count of 2: "00"`,
+		`Valid: $NUMBER: ("." DIGIT{2..3})? with a count of 1: "0.00"`,
+		`Invalid: $NUMBER: ("." DIGIT{2..3})? with a count of 2: "0.00.00"`,
+		`Invalid: $NUMBER: "." with a count of 2: "0..00"`,
+		`Valid: $NUMBER: DIGIT{2..3} with a count of 3: "0.000"`,
+		`Invalid: $NUMBER: DIGIT{2..3} with a count of 1: "0.0"`,
+	}
+	var actual []string
+	var iterator = examples.GetIterator()
+	for iterator.HasNext() {
+		actual = append(actual, fmt.Sprintf("%v", iterator.GetNext()))
+	}
+	ass.Equal(t, expected, actual)
+
+	// Every example for a larger grammar agrees with the interpreter.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	document = parser.ParseDocument(string(bytes))
+	examples, err = cds.ExemplifierClass().FromDocument(document).GenerateExamples("$document")
+	ass.Nil(t, err)
+	ass.True(t, examples.GetSize() > 100)
+	var interpreter = cds.InterpreterClass().FromDocument(document)
+	iterator = examples.GetIterator()
+	for iterator.HasNext() {
+		var example = iterator.GetNext()
+		var _, err = interpreter.ParseSource(example.GetSource())
+		ass.Equal(t, example.IsValid(), err == nil, example.GetDescription())
+	}
+
+	// The start symbol must name a rule.
+	_, err = exemplifier.GenerateExamples("$NUMBER")
+	ass.Equal(t, "The examples for $NUMBER could not be generated: The start symbol must name a rule definition: $NUMBER", err.Error())
+}
+
+func TestRenameSymbol(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var source = `!> A list of items. <!
+$document: list EOF
+$list: "[" (ITEM ("," ITEM)*)? "]"  ! Each ITEM is separated by a comma.
+$ITEM: ~(SEPARATOR | "]")+
+$SEPARATOR: "," | EOL
+`
+	var document = parser.ParseDocument(source)
+	var refactorer = cds.RefactorerClass().Default()
+	var formatted, err = refactorer.RenameSymbol(document, "$ITEM", "$ELEMENT")
+	ass.Nil(t, err)
+	ass.Equal(t, `!> A list of items. <!
+$document: list EOF
+$list: "[" (ELEMENT ("," ELEMENT)*)? "]"  ! Each ITEM is separated by a comma.
+$ELEMENT: ~(SEPARATOR | "]")+
+$SEPARATOR: "," | EOL
+`, formatted)
+	formatted, err = refactorer.RenameSymbol(document, "$list", "$sequence")
+	ass.Nil(t, err)
+	ass.True(t, sts.Contains(formatted, "$document: sequence EOF\n$sequence: "))
+
+	// Refuse renames that would change the meaning of the grammar.
+	var expected = []string{
+		"The symbol $MISSING could not be renamed: The symbol is not defined: $MISSING",
+		"The symbol $ELEMENT could not be renamed: The new symbol is not valid: $9LIVES",
+		"The symbol $ELEMENT could not be renamed: The new symbol is already defined: $SEPARATOR",
+		"The symbol $ELEMENT could not be renamed: The new symbol $element must also name a token.",
+		"The symbol $sequence could not be renamed: The new symbol $LIST must also name a rule.",
+		"The symbol $SEPARATOR could not be renamed: The new symbol is the name of an intrinsic: $EOL",
+	}
+	var renames = [][2]string{
+		{"$MISSING", "$OTHER"},
+		{"$ELEMENT", "$9LIVES"},
+		{"$ELEMENT", "$SEPARATOR"},
+		{"$ELEMENT", "$element"},
+		{"$sequence", "$LIST"},
+		{"$SEPARATOR", "$EOL"},
+	}
+	for index, rename := range renames {
+		_, err = refactorer.RenameSymbol(document, rename[0], rename[1])
+		ass.Equal(t, expected[index], err.Error())
+	}
+	ass.Equal(t, formatted, cds.FormatterClass().Default().FormatDocument(document))
+}
+
+func TestNormalizer(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var formatter = cds.FormatterClass().Default()
+	var normalizer = cds.NormalizerClass().Default()
+	var source = `$document: (item ("," item){0..2})? EOF
+$item: NUMBER{2..} | (NAME) | ~(",")
+$NAME: ('a'..'z'){1..3} (~(DIGIT))
+$NUMBER: DIGIT+
+`
+	var document = parser.ParseDocument(source)
+	normalizer.ExpandConstraints(document)
+	ass.Equal(t, `$document: (item (("," item) ("," item)?)?)? EOF
+$item: NUMBER NUMBER+ | (NAME) | ~(",")
+$NAME: ('a'..'z') (('a'..'z') ('a'..'z')?)? (~(DIGIT))
+$NUMBER: DIGIT+
+`, formatter.FormatDocument(document))
+
+	document = parser.ParseDocument(source)
+	normalizer.FlattenPrecedences(document)
+	ass.Equal(t, `$document: (item ("," item){0..2})? EOF
+$item: NUMBER{2..} | NAME | ~","
+$NAME: 'a'..'z'{1..3} ~DIGIT
+$NUMBER: DIGIT+
+`, formatter.FormatDocument(document))
+
+	document = parser.ParseDocument(source)
+	normalizer.ExtractPrecedences(document)
+	ass.Equal(t, `$document: document_1? EOF
+$document_1: item document_2{0..2}
+$document_2: "," item
+$item: NUMBER{2..} | item_1 | ~(",")
+$item_1: NAME
+$NAME: ('a'..'z'){1..3} (~(DIGIT))
+$NUMBER: DIGIT+
+`, formatter.FormatDocument(document))
+
+	document = parser.ParseDocument(source)
+	normalizer.RemoveRepetitions(document)
+	ass.Equal(t, `$document: (item (("," item) ("," item)?)?)? EOF
+$item: NUMBER item_1 | (NAME) | ~(",")
+$item_1: NUMBER item_1?
+$NAME: ('a'..'z') (('a'..'z') ('a'..'z')?)? (~(DIGIT))
+$NUMBER: DIGIT+
+`, formatter.FormatDocument(document))
+
+	// A repetition of something that can match nothing cannot be removed.
+	document = parser.ParseDocument(`$document: ("a"? "b"?)* EOF
+`)
+	ass.PanicsWithValue(t, "The repetition within $document cannot be removed since it can match nothing.", func() {
+		normalizer.RemoveRepetitions(document)
+	})
+	ass.Equal(t, "$document: (\"a\"? \"b\"?)* EOF\n", formatter.FormatDocument(document))
+
+	// Each transform of each grammar still validates and accepts the same
+	// sentences.
+	var validator = cds.ValidatorClass().Default()
+	var transforms = []func(cds.DocumentLike){
+		normalizer.ExpandConstraints,
+		normalizer.ExtractPrecedences,
+		normalizer.FlattenPrecedences,
+		normalizer.RemoveRepetitions,
+	}
+	for _, name := range []string{"cdcn.cdsn", "cdsn.cdsn"} {
+		var bytes, _ = osx.ReadFile(grammarsDirectory + name)
+		var sampler = cds.SamplerClass().FromDocument(parser.ParseDocument(string(bytes)))
+		var sentences []string
+		for i := 0; i < 20; i++ {
+			var sentence, _ = sampler.SampleSentence("$document")
+			sentences = append(sentences, sentence)
+		}
+		for _, transform := range transforms {
+			var document = parser.ParseDocument(string(bytes))
+			transform(document)
+			validator.ValidateDocument(document)
+			var formatted = formatter.FormatDocument(document)
+			ass.Equal(t, formatted, formatter.FormatDocument(parser.ParseDocument(formatted)))
+			if name == "cdcn.cdsn" {
+				var interpreter = cds.InterpreterClass().FromDocument(document)
+				for _, sentence := range sentences {
+					var _, err = interpreter.ParseSource(sentence)
+					ass.Nil(t, err, sentence)
+				}
+			}
+		}
+	}
+}
+
+func TestInlineAndExtract(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var formatter = cds.FormatterClass().Default()
+	var validator = cds.ValidatorClass().Default()
+
+	// Refactor the collection notation and check that it still accepts the
+	// same sentences.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdcn.cdsn")
+	var document = parser.ParseDocument(string(bytes))
+	var sampler = cds.SamplerClass().FromDocument(document)
+	var sentences []string
+	for i := 0; i < 20; i++ {
+		var sentence, _ = sampler.SampleSentence("$document")
+		sentences = append(sentences, sentence)
+	}
+	var grammar = document.GetGrammar()
+	grammar.Inline("$key")
+	var definitions = map[string]cds.DefinitionLike{}
+	var iterator = grammar.GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			definitions[definition.GetSymbol()] = definition
+		}
+	}
+	ass.Nil(t, definitions["$key"])
+	ass.Equal(t, "$association: primitive \":\" value", formatter.FormatDefinition(definitions["$association"]))
+	grammar.Extract(definitions["$collection"], 1, 2, 2, "$contents")
+	var formatted = formatter.FormatDocument(document)
+	ass.Contains(t, formatted, "$collection: \"[\" contents \"]\" context\n$contents: associations | values\n")
+	validator.ValidateDocument(document)
+	ass.Equal(t, formatted, formatter.FormatDocument(parser.ParseDocument(formatted)))
+	var interpreter = cds.InterpreterClass().FromDocument(document)
+	for _, sentence := range sentences {
+		var _, err = interpreter.ParseSource(sentence)
+		ass.Nil(t, err, sentence)
+	}
+
+	// Notes and comments are preserved.
+	var source = `!>
+    EXAMPLE
+<!
+$document: list EOF
+$list: "[" items? "]"
+$items: item ("," item)*
+$item: pair | entry | NAME | list
+$pair: NAME ":" NAME
+
+!>
+    An entry is tagged.
+<!
+$entry: "@" NAME ":" NAME
+$NAME: LETTER+
+$LETTER: 'a'..'z'  ! Lower case only.
+`
+	document = parser.ParseDocument(source)
+	grammar = document.GetGrammar()
+	grammar.Inline("$items")
+	grammar.Inline("$LETTER")
+	var pair = grammar.GetStatements().AsArray()[4].GetDefinition()
+	grammar.Extract(pair, 1, 2, 3, "$value")
+	formatted = formatter.FormatDocument(document)
+	ass.Equal(t, `!>
+    EXAMPLE
+<!
+$document: list EOF
+$list: "[" (item ("," item)*)? "]"
+$item: pair | entry | NAME | list
+$pair: NAME value
+$value: ":" NAME
+
+!>
+    An entry is tagged.
+<!
+$entry: "@" NAME ":" NAME
+$NAME: (
+    'a'..'z'  ! Lower case only.
+)+
+`, formatted)
+	validator.ValidateDocument(document)
+	ass.Equal(t, formatted, formatter.FormatDocument(parser.ParseDocument(formatted)))
+
+	// Repeated factors are only reused within the chosen alternative, and the
+	// comments that only describe an inlined definition are removed.
+	document = parser.ParseDocument(`$document: list EOF
+$list: "(" NAME ":" NAME ")" NAME ":" NAME | NAME ":" NAME
+$NAME: LETTER+
+
+!>
+    A letter is lower case.
+<!
+$LETTER: 'a'..'z'
+`)
+	grammar = document.GetGrammar()
+	var list = grammar.GetStatements().AsArray()[1].GetDefinition()
+	grammar.Extract(list, 1, 2, 4, "$pair")
+	grammar.Inline("$LETTER")
+	formatted = formatter.FormatDocument(document)
+	ass.Equal(t, `$document: list EOF
+$list: "(" pair ")" pair | NAME ":" NAME
+$pair: NAME ":" NAME
+$NAME: 'a'..'z'+
+`, formatted)
+	validator.ValidateDocument(document)
+
+	// Unsafe refactorings are refused.
+	document = parser.ParseDocument(source + "$tail: \",\" tail?\n")
+	grammar = document.GetGrammar()
+	var statements = grammar.GetStatements().AsArray()
+	ass.PanicsWithValue(t, "The symbol is not defined: $missing", func() {
+		grammar.Inline("$missing")
+	})
+	ass.PanicsWithValue(t, "The symbol $tail cannot be inlined since its definition is recursive.", func() {
+		grammar.Inline("$tail")
+	})
+	ass.PanicsWithValue(t, "The symbol $document cannot be inlined since it is not referenced.", func() {
+		grammar.Inline("$document")
+	})
+	ass.PanicsWithValue(t, "The token $NAME cannot be inlined into the rule $item.", func() {
+		grammar.Inline("$NAME")
+	})
+	ass.PanicsWithValue(t, "The definition $list does not have an alternative 2.", func() {
+		grammar.Extract(statements[2].GetDefinition(), 2, 1, 1, "$open")
+	})
+	ass.PanicsWithValue(t, "The alternative 1 does not have the factors 2..4.", func() {
+		grammar.Extract(statements[2].GetDefinition(), 1, 2, 4, "$open")
+	})
+	ass.PanicsWithValue(t, "The new symbol $OPEN must name a rule.", func() {
+		grammar.Extract(statements[2].GetDefinition(), 1, 1, 1, "$OPEN")
+	})
+	ass.PanicsWithValue(t, "The new symbol is already defined: $item", func() {
+		grammar.Extract(statements[2].GetDefinition(), 1, 1, 1, "$item")
+	})
+	ass.Equal(t, source+"$tail: \",\" tail?\n", formatter.FormatDocument(document))
+}
+
+func TestDiffer(t *tes.T) {
+	var parser = cds.ParserClass().Default()
+	var differ = cds.DifferClass().Default()
+	var oldDocument = parser.ParseDocument(`!>
+    EXAMPLE
+<!
+$document: list EOF
+$list: "[" items? "]"
+$items: item ("," item)*
+$item: NAME | list
+$key: NAME
+$NAME: 'a'..'f'+  ! Lower case letters.
+$DIGIT: '0'..'9'
+`)
+	var newDocument = parser.ParseDocument(`$document: list EOF  ! A list.
+$list:
+    "[" items* "]"
+    "(" ")"
+
+$items: item ("," item | ";" item)*
+$item: NAME | list | NUMBER
+$NAME: 'a'..'z'+
+$DIGIT: '0'..'7'
+$NUMBER: DIGIT+
+`)
+	var changes []string
+	var iterator = differ.DiffDocuments(oldDocument, newDocument).GetIterator()
+	for iterator.HasNext() {
+		changes = append(changes, fmt.Sprintf("%v", iterator.GetNext()))
+	}
+	ass.Equal(t, []string{
+		"Modified: $list\n    alternative 1, factor 2: widened cardinality of items from ? to *\n    added alternative 2: \"(\" \")\"",
+		"Modified: $items\n    alternative 1, factor 2, added alternative 2: \";\" item",
+		"Modified: $item\n    added alternative 3: NUMBER",
+		"Removed: $key",
+		"Modified: $NAME\n    alternative 1, factor 1: widened glyph range from 'a'..'f' to 'a'..'z'",
+		"Modified: $DIGIT\n    alternative 1, factor 1: narrowed glyph range from '0'..'9' to '0'..'7'",
+		"Added: $NUMBER",
+	}, changes)
+
+	// Reordered alternatives are moved rather than removed and added.
+	oldDocument = parser.ParseDocument(`$document: ("a" | "b") EOF
+$list: "x" | "y" | "z"
+`)
+	newDocument = parser.ParseDocument(`$document: ("b" | "a") EOF
+$list: "z" | "y" | "x"
+`)
+	changes = nil
+	iterator = differ.DiffDocuments(oldDocument, newDocument).GetIterator()
+	for iterator.HasNext() {
+		changes = append(changes, fmt.Sprintf("%v", iterator.GetNext()))
+	}
+	ass.Equal(t, []string{
+		"Modified: $document\n    alternative 1, factor 1, moved alternative 2 to 1: \"b\"",
+		"Modified: $list\n    moved alternative 3 to 1: \"z\"",
+	}, changes)
+
+	// Comments, notes and formatting do not change a grammar.
+	var bytes, _ = osx.ReadFile(grammarsDirectory + "cdsn.cdsn")
+	var document = parser.ParseDocument(string(bytes))
+	var formatted = cds.FormatterClass().Default().FormatDocument(document)
+	ass.True(t, differ.DiffDocuments(document, parser.ParseDocument(formatted)).IsEmpty())
+	ass.True(t, differ.DiffDocuments(document, newDocument).GetSize() > 0)
+}
@@ -48,6 +48,7 @@ func (c *precedenceClass_) FromExpression(
 
 type precedence_ struct {
 	expression ExpressionLike
+	span       SpanLike
 }
 
 // Public Interface
@@ -56,9 +57,17 @@ func (v *precedence_) GetExpression() ExpressionLike {
 	return v.expression
 }
 
+func (v *precedence_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *precedence_) SetExpression(expression ExpressionLike) {
 	if expression == nil {
 		panic("The expression within a precedence cannot be nil.")
 	}
 	v.expression = expression
 }
+
+func (v *precedence_) SetSpan(span SpanLike) {
+	v.span = span
+}
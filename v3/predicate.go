@@ -51,6 +51,7 @@ func (c *predicateClass_) FromAssertion(
 type predicate_ struct {
 	assertion  AssertionLike
 	isInverted bool
+	span       SpanLike
 }
 
 // Public Interface
@@ -59,6 +60,10 @@ func (v *predicate_) GetAssertion() AssertionLike {
 	return v.assertion
 }
 
+func (v *predicate_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *predicate_) IsInverted() bool {
 	return v.isInverted
 }
@@ -73,3 +78,7 @@ func (v *predicate_) SetAssertion(assertion AssertionLike) {
 func (v *predicate_) SetInverted(isInverted bool) {
 	v.isInverted = isInverted
 }
+
+func (v *predicate_) SetSpan(span SpanLike) {
+	v.span = span
+}
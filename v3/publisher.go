@@ -0,0 +1,274 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type publisherClass_ struct {
+	defaultTitle string
+}
+
+// Private Class Namespace Reference
+
+var publisherClass = &publisherClass_{
+	// The title of a page whose grammar does not begin with a comment.
+	defaultTitle: "CDSN Grammar",
+}
+
+// Public Class Namespace Access
+
+func PublisherClass() PublisherClassLike {
+	return publisherClass
+}
+
+// Public Class Constructors
+
+func (c *publisherClass_) Default() PublisherLike {
+	var publisher = &publisher_{}
+	return publisher
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type publisher_ struct {
+	result sts.Builder
+	usedBy map[string]col.SetLike[string] // The names that use each name.
+}
+
+// Public Interface
+
+func (v *publisher_) PublishDocument(document DocumentLike) string {
+	v.result.Reset()
+	var statements = document.GetGrammar().GetStatements()
+	var names = v.indexDocument(document)
+	var title = publisherClass.defaultTitle
+	var iterator = statements.GetIterator()
+	if iterator.HasNext() {
+		var comment = iterator.GetNext().GetComment()
+		if len(comment) > 0 {
+			title, _ = splitComment(comment)
+		}
+	}
+	v.result.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n")
+	v.result.WriteString("<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&v.result, "<title>%v</title>\n", escapeXML(title))
+	v.result.WriteString(publisherStyle_)
+	v.result.WriteString("</head>\n<body>\n")
+	iterator.ToStart()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		var comment = statement.GetComment()
+		if len(comment) > 0 {
+			v.publishComment(comment)
+		} else {
+			v.publishDefinition(statement.GetDefinition())
+		}
+	}
+	v.publishIndex(names)
+	v.result.WriteString("</body>\n</html>\n")
+	return v.result.String()
+}
+
+// Private Interface
+
+// This private class method records the definitions that use each name within
+// the specified document and returns the names of all of its definitions.
+func (v *publisher_) indexDocument(document DocumentLike) col.SetLike[string] {
+	var names = col.SetClass[string]().Empty()
+	v.usedBy = make(map[string]col.SetLike[string])
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition == nil {
+			continue
+		}
+		var name = definition.GetSymbol()[1:]
+		names.AddValue(name)
+		for _, used := range collectNames(definition) {
+			if v.usedBy[used] == nil {
+				v.usedBy[used] = col.SetClass[string]().Empty()
+			}
+			v.usedBy[used].AddValue(name)
+		}
+	}
+	return names
+}
+
+func (v *publisher_) publishAlternative(alternative AlternativeLike) string {
+	var factors []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		factors = append(factors, v.publishFactor(iterator.GetNext()))
+	}
+	return sts.Join(factors, " ")
+}
+
+// This private class method returns the specified assertion as HTML with each
+// name linked to its definition.
+func (v *publisher_) publishAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil && len(element.GetName()) > 0:
+		return publishLink(element.GetName())
+	case precedence != nil:
+		var alternatives []string
+		var iterator = precedence.GetExpression().GetAlternatives().GetIterator()
+		for iterator.HasNext() {
+			alternatives = append(alternatives, v.publishAlternative(iterator.GetNext()))
+		}
+		return "(" + sts.Join(alternatives, " | ") + ")"
+	default:
+		return escapeXML(describeAssertion(assertion))
+	}
+}
+
+// This private class method appends the specified comment as a section of
+// prose.  The first line of the comment is its heading, blank lines separate
+// its paragraphs and lines beginning with "*" are items in a list.
+func (v *publisher_) publishComment(comment string) {
+	var heading, lines = splitComment(comment)
+	v.result.WriteString("<section class=\"comment\">\n")
+	fmt.Fprintf(&v.result, "<h2>%v</h2>\n", escapeXML(heading))
+	var paragraph []string
+	var items []string
+	lines = append(lines, "")
+	for _, line := range lines {
+		if len(paragraph) > 0 && (len(line) == 0 || sts.HasPrefix(line, "* ")) {
+			fmt.Fprintf(&v.result, "<p>%v</p>\n", escapeXML(sts.Join(paragraph, " ")))
+			paragraph = nil
+		}
+		if len(items) > 0 && !sts.HasPrefix(line, "* ") {
+			v.result.WriteString("<ul>\n")
+			for _, item := range items {
+				fmt.Fprintf(&v.result, "<li>%v</li>\n", escapeXML(item))
+			}
+			v.result.WriteString("</ul>\n")
+			items = nil
+		}
+		switch {
+		case sts.HasPrefix(line, "* "):
+			items = append(items, line[2:])
+		case len(line) > 0:
+			paragraph = append(paragraph, line)
+		}
+	}
+	v.result.WriteString("</section>\n")
+}
+
+// This private class method appends the specified definition with each of its
+// alternatives on a separate line, annotated by its note, if any.
+func (v *publisher_) publishDefinition(definition DefinitionLike) {
+	var symbol = definition.GetSymbol()
+	var name = escapeXML(symbol[1:])
+	fmt.Fprintf(&v.result, "<section class=\"definition\" id=\"%v\">\n", name)
+	fmt.Fprintf(&v.result, "<h3><a href=\"#%v\">%v</a></h3>\n", name, escapeXML(symbol))
+	v.result.WriteString("<ul class=\"alternatives\">\n")
+	var iterator = definition.GetExpression().GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		var alternative = iterator.GetNext()
+		fmt.Fprintf(&v.result, "<li><code>%v</code>", v.publishAlternative(alternative))
+		var note = alternative.GetNote()
+		if len(note) > 0 {
+			var text = sts.TrimSpace(sts.TrimPrefix(note, "!"))
+			fmt.Fprintf(&v.result, " <span class=\"note\">%v</span>", escapeXML(text))
+		}
+		v.result.WriteString("</li>\n")
+	}
+	v.result.WriteString("</ul>\n</section>\n")
+}
+
+func (v *publisher_) publishFactor(factor FactorLike) string {
+	var predicate = factor.GetPredicate()
+	var text = v.publishAssertion(predicate.GetAssertion())
+	if predicate.IsInverted() {
+		text = "~" + text
+	}
+	var cardinality = factor.GetCardinality()
+	if cardinality != nil {
+		var formatter = FormatterClass().Default().(*formatter_)
+		formatter.formatCardinality(cardinality)
+		text += formatter.getResult()
+	}
+	return text
+}
+
+// This private class method appends the cross-reference index listing the
+// definitions that use each of the specified names.
+func (v *publisher_) publishIndex(names col.SetLike[string]) {
+	v.result.WriteString("<section class=\"index\" id=\"index_\">\n<h2>INDEX</h2>\n")
+	v.result.WriteString("<table>\n<tr><th>Name</th><th>Used By</th></tr>\n")
+	var iterator = names.GetIterator()
+	for iterator.HasNext() {
+		var name = iterator.GetNext()
+		var links = "<em>unused</em>"
+		var usedBy = v.usedBy[name]
+		if usedBy != nil {
+			var users []string
+			for _, user := range usedBy.AsArray() {
+				users = append(users, publishLink(user))
+			}
+			links = sts.Join(users, ", ")
+		}
+		fmt.Fprintf(&v.result, "<tr><td>%v</td><td>%v</td></tr>\n", publishLink(name), links)
+	}
+	v.result.WriteString("</table>\n</section>\n")
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns a link to the definition of the specified
+// name.
+func publishLink(name string) string {
+	name = escapeXML(name)
+	return fmt.Sprintf("<a href=\"#%v\">%v</a>", name, name)
+}
+
+// This private function returns the heading of the specified comment and its
+// remaining lines with any surrounding spaces removed.
+func splitComment(comment string) (heading string, lines []string) {
+	var text = sts.TrimSuffix(sts.TrimPrefix(comment, "!>"), "<!")
+	for _, line := range sts.Split(text, "\n") {
+		line = sts.TrimSpace(line)
+		switch {
+		case len(heading) > 0:
+			lines = append(lines, line)
+		case len(line) > 0:
+			heading = line
+		}
+	}
+	return heading, lines
+}
+
+// This private constant defines the style sheet that is embedded within each
+// HTML page.
+const publisherStyle_ = `<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 50em; }
+code { font-family: monospace; }
+section.definition { border-left: 3px solid #ccd; margin: 1em 0; padding-left: 1em; }
+section.definition h3 { font-family: monospace; margin: 0; }
+ul.alternatives { list-style: none; padding-left: 1em; }
+span.note { color: #666; font-style: italic; margin-left: 1em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccd; padding: 0.25em 0.5em; text-align: left; }
+a { text-decoration: none; }
+</style>
+`
@@ -0,0 +1,128 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type refactorerClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var refactorerClass = &refactorerClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func RefactorerClass() RefactorerClassLike {
+	return refactorerClass
+}
+
+// Public Class Constructors
+
+func (c *refactorerClass_) Default() RefactorerLike {
+	var refactorer = &refactorer_{}
+	return refactorer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type refactorer_ struct {
+	// This class does not define any attributes.
+}
+
+// Public Interface
+
+func (v *refactorer_) RenameSymbol(
+	document DocumentLike,
+	oldSymbol string,
+	newSymbol string,
+) (formatted string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			err = fmt.Errorf("The symbol %v could not be renamed: %v", oldSymbol, message)
+		}
+	}()
+	var definitions = collectDefinitions(document.GetGrammar())
+	var definition = definitions[oldSymbol]
+	if definition == nil {
+		panic(fmt.Sprintf("The symbol is not defined: %v", oldSymbol))
+	}
+	var newName = checkSymbol(newSymbol)
+	var oldName = oldSymbol[1:]
+	if isRuleName(oldName) != isRuleName(newName) {
+		var kind = "token"
+		if isRuleName(oldName) {
+			kind = "rule"
+		}
+		panic(fmt.Sprintf("The new symbol %v must also name a %v.", newSymbol, kind))
+	}
+	if newSymbol != oldSymbol && definitions[newSymbol] != nil {
+		panic(fmt.Sprintf("The new symbol is already defined: %v", newSymbol))
+	}
+
+	// Rename the definition and the references to it.
+	definition.SetSymbol(newSymbol)
+	var renamer = &nameRenamer_{
+		VisitorLike: VisitorClass().Default(),
+		names:       col.CatalogClass[string, string]().Empty(),
+	}
+	renamer.names.SetValue(oldName, newName)
+	WalkerClass().FromVisitor(renamer).WalkDocument(document)
+	formatted = FormatterClass().Default().FormatDocument(document)
+	return formatted, err
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the name of the specified new symbol or panics
+// if the symbol cannot be used to name a definition.
+func checkSymbol(symbol string) string {
+	var matches = ScannerClass().MatchSymbol(symbol)
+	if len(matches) == 0 || matches[0] != symbol {
+		panic(fmt.Sprintf("The new symbol is not valid: %v", symbol))
+	}
+	var name = matches[1]
+	matches = ScannerClass().MatchIntrinsic(name)
+	if len(matches) > 0 && matches[0] == name {
+		panic(fmt.Sprintf("The new symbol is the name of an intrinsic: %v", symbol))
+	}
+	return name
+}
+
+// This private function returns the definitions in the specified grammar keyed
+// by symbol.
+func collectDefinitions(grammar GrammarLike) map[string]DefinitionLike {
+	var definitions = make(map[string]DefinitionLike)
+	var iterator = grammar.GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			definitions[definition.GetSymbol()] = definition
+		}
+	}
+	return definitions
+}
@@ -0,0 +1,441 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+	rnd "math/rand"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type samplerClass_ struct {
+	alphabet           []rune
+	defaultDepth       int
+	defaultRepetitions int
+	defaultSeed        int64
+	escapes            []string
+	intrinsics         map[string][]rune
+	maximumAttempts    int
+}
+
+// Private Class Namespace Reference
+
+var samplerClass = &samplerClass_{
+	// The characters from which the characters excluded by an inverted token
+	// assertion are sampled.
+	alphabet: []rune(" !\"#$%&'()*+,-./0123456789:;<=>?@" +
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~" +
+		"\téΩ中"),
+	defaultDepth:       10,
+	defaultRepetitions: 3,
+	defaultSeed:        1,
+	escapes: []string{
+		`\a`, `\b`, `\f`, `\n`, `\r`, `\t`, `\v`, `\'`, `\"`, `\\`,
+		`\x7f`, `\u00e9`, `\U0001f600`,
+	},
+	// The characters from which each single character intrinsic is sampled.
+	// The ANY intrinsic is limited to letters, digits and spaces so that the
+	// samples remain readable.
+	intrinsics: map[string][]rune{
+		"ANY":     []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 "),
+		"CONTROL": []rune("\a\b\t\v\f\r\x1b\x7f"),
+		"DIGIT":   []rune("0123456789"),
+		"LOWER":   []rune("abcdefghijklmnopqrstuvwxyz"),
+		"UPPER":   []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+	},
+	maximumAttempts: 100,
+}
+
+// Public Class Namespace Access
+
+func SamplerClass() SamplerClassLike {
+	return samplerClass
+}
+
+// Public Class Constructors
+
+func (c *samplerClass_) FromDocument(document DocumentLike) SamplerLike {
+	return c.FromDocumentAndSeed(document, c.defaultSeed)
+}
+
+func (c *samplerClass_) FromDocumentAndSeed(
+	document DocumentLike,
+	seed int64,
+) SamplerLike {
+	ValidatorClass().Default().ValidateDocument(document)
+	var sampler = &sampler_{
+		definitions:        make(map[string]DefinitionLike),
+		document:           document,
+		generator:          rnd.New(rnd.NewSource(seed)),
+		heights:            make(map[string]int),
+		interpreters:       make(map[string]InterpreterLike),
+		maximumDepth:       c.defaultDepth,
+		maximumRepetitions: c.defaultRepetitions,
+		seed:               seed,
+	}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var definition = iterator.GetNext().GetDefinition()
+		if definition != nil {
+			sampler.definitions[definition.GetSymbol()[1:]] = definition
+		}
+	}
+	sampler.matcher = matcherClass.fromRunes(nil, sampler.definitions)
+	sampler.terminals = collectTerminals(document)
+	sampler.analyzeHeights()
+	return sampler
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type sampler_ struct {
+	definitions        map[string]DefinitionLike // The definitions keyed by name.
+	depth              int                       // The nesting depth of names.
+	document           DocumentLike
+	generator          *rnd.Rand
+	heights            map[string]int             // The fewest nested names needed by each definition.
+	interpreters       map[string]InterpreterLike // The interpreters keyed by rule symbol.
+	isToken            bool                       // Whether or not a token is being sampled.
+	matcher            *matcher_
+	maximumDepth       int
+	maximumRepetitions int
+	seed               int64
+	terminals          []terminal_ // The terminals that may appear within rules.
+}
+
+// Public Interface
+
+func (v *sampler_) GetMaximumDepth() int {
+	return v.maximumDepth
+}
+
+func (v *sampler_) GetMaximumRepetitions() int {
+	return v.maximumRepetitions
+}
+
+func (v *sampler_) GetSeed() int64 {
+	return v.seed
+}
+
+func (v *sampler_) SampleSentence(symbol string) (sentence string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			err = fmt.Errorf("A sentence for %v could not be sampled: %v", symbol, message)
+		}
+	}()
+	var name = sts.TrimPrefix(symbol, "$")
+	if _, ok := v.definitions[name]; !ok {
+		panic(fmt.Sprintf("The symbol is not defined: %v", symbol))
+	}
+	if v.heights[name] == unreachable_ {
+		panic("The definition can never be completed without recursing forever.")
+	}
+	v.depth = 0
+	v.isToken = false
+	if !isRuleName(name) {
+		sentence = v.sampleName(name)
+		return sentence, err
+	}
+
+	// A terminal that runs to the end of a line, like a note, swallows any
+	// terminals that follow it on the same line, so each sentence is checked
+	// against the longest terminals and sampled again if necessary.
+	var interpreter, ok = v.interpreters[symbol]
+	if !ok {
+		interpreter = InterpreterClass().FromDocumentAndSymbol(v.document, "$"+name)
+		v.interpreters[symbol] = interpreter
+	}
+	for attempt := 0; attempt < samplerClass.maximumAttempts; attempt++ {
+		sentence = v.sampleName(name)
+		if _, e := interpreter.ParseSource(sentence); e == nil {
+			return sentence, err
+		}
+	}
+	panic("No sentence could be sampled that is not changed by the longest terminals.")
+}
+
+func (v *sampler_) SetMaximumDepth(depth int) {
+	if depth < 0 {
+		panic(fmt.Sprintf("The maximum depth cannot be negative: %v", depth))
+	}
+	v.maximumDepth = depth
+}
+
+func (v *sampler_) SetMaximumRepetitions(repetitions int) {
+	if repetitions < 0 {
+		panic(fmt.Sprintf("The maximum repetitions cannot be negative: %v", repetitions))
+	}
+	v.maximumRepetitions = repetitions
+}
+
+// Private Interface
+
+// This private class method determines for each definition the fewest nested
+// names that must be expanded to complete a sample of it.  The heights are
+// lowered repeatedly until they stop changing.  A definition that can never be
+// completed keeps an unreachable height.
+func (v *sampler_) analyzeHeights() {
+	for name := range v.definitions {
+		v.heights[name] = unreachable_
+	}
+	var isChanged = true
+	for isChanged {
+		isChanged = false
+		for name, definition := range v.definitions {
+			var height = v.heightOfExpression(definition.GetExpression())
+			if height < unreachable_ {
+				height++
+			}
+			if height < v.heights[name] {
+				v.heights[name] = height
+				isChanged = true
+			}
+		}
+	}
+}
+
+func (v *sampler_) heightOfAlternative(alternative AlternativeLike) int {
+	var height int
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		height = max(height, v.heightOfFactor(iterator.GetNext()))
+	}
+	return height
+}
+
+func (v *sampler_) heightOfAssertion(assertion AssertionLike) int {
+	var element = assertion.GetElement()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil && len(element.GetName()) > 0:
+		return v.heights[element.GetName()]
+	case precedence != nil:
+		return v.heightOfExpression(precedence.GetExpression())
+	default:
+		return 0
+	}
+}
+
+func (v *sampler_) heightOfExpression(expression ExpressionLike) int {
+	var height = unreachable_
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		height = min(height, v.heightOfAlternative(iterator.GetNext()))
+	}
+	return height
+}
+
+func (v *sampler_) heightOfFactor(factor FactorLike) int {
+	var minimum, _ = getLimits(factor.GetCardinality())
+	var predicate = factor.GetPredicate()
+	if minimum == 0 || predicate.IsInverted() {
+		return 0
+	}
+	return v.heightOfAssertion(predicate.GetAssertion())
+}
+
+// This private class method determines whether or not the specified assertion
+// matches exactly the specified text.
+func (v *sampler_) matchesText(assertion AssertionLike, text string) bool {
+	var runes = []rune(text)
+	var matcher = matcherClass.fromRunes(runes, v.definitions)
+	return matcher.matchAssertion(assertion, 0, func(next int) bool {
+		return next == len(runes)
+	})
+}
+
+// This private class method returns a random rune from the specified runes.
+func (v *sampler_) pickRune(runes []rune) rune {
+	return runes[v.generator.Intn(len(runes))]
+}
+
+func (v *sampler_) sampleAlternative(alternative AlternativeLike) string {
+	var samples []string
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		samples = append(samples, v.sampleFactor(iterator.GetNext()))
+	}
+	return joinSamples(samples, v.isToken)
+}
+
+func (v *sampler_) sampleAssertion(assertion AssertionLike) string {
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		return v.sampleElement(element)
+	case glyph != nil:
+		var first = unquoteCharacter(glyph.GetFirst())
+		var last = first
+		if len(glyph.GetLast()) > 0 {
+			last = unquoteCharacter(glyph.GetLast())
+		}
+		return string(first + rune(v.generator.Intn(int(last-first)+1)))
+	case precedence != nil:
+		return v.sampleExpression(precedence.GetExpression())
+	default:
+		panic("Attempted to sample an empty assertion.")
+	}
+}
+
+func (v *sampler_) sampleElement(element ElementLike) string {
+	var intrinsic = element.GetIntrinsic()
+	var literal = element.GetLiteral()
+	var name = element.GetName()
+	switch {
+	case intrinsic == "EOF":
+		return ""
+	case intrinsic == "EOL":
+		return "\n"
+	case intrinsic == "ESCAPE":
+		return samplerClass.escapes[v.generator.Intn(len(samplerClass.escapes))]
+	case len(intrinsic) > 0:
+		return string(v.pickRune(samplerClass.intrinsics[intrinsic]))
+	case len(literal) > 0:
+		return unquoteLiteral(literal)
+	case len(name) > 0:
+		return v.sampleName(name)
+	default:
+		panic("Attempted to sample an empty element.")
+	}
+}
+
+// This private class method samples one of the alternatives of the specified
+// expression at random.  Beyond the maximum depth only the alternatives that
+// can be completed with the fewest nested names are chosen so that the sample
+// is guaranteed to terminate.
+func (v *sampler_) sampleExpression(expression ExpressionLike) string {
+	var alternatives = expression.GetAlternatives().AsArray()
+	if v.depth > v.maximumDepth {
+		var height = v.heightOfExpression(expression)
+		var shortest []AlternativeLike
+		for _, alternative := range alternatives {
+			if v.heightOfAlternative(alternative) == height {
+				shortest = append(shortest, alternative)
+			}
+		}
+		alternatives = shortest
+	}
+	var alternative = alternatives[v.generator.Intn(len(alternatives))]
+	return v.sampleAlternative(alternative)
+}
+
+// This private class method samples the predicate of the specified factor a
+// random number of times within the limits of its cardinality.  An unlimited
+// maximum is replaced by the maximum repetitions, and beyond the maximum depth
+// only the minimum number of repetitions is sampled.
+func (v *sampler_) sampleFactor(factor FactorLike) string {
+	var minimum, maximum = getLimits(factor.GetCardinality())
+	if maximum < 0 {
+		maximum = max(minimum, v.maximumRepetitions)
+	}
+	var count = minimum
+	if v.depth <= v.maximumDepth {
+		count += v.generator.Intn(maximum - minimum + 1)
+	}
+	var samples []string
+	var predicate = factor.GetPredicate()
+	for i := 0; i < count; i++ {
+		samples = append(samples, v.samplePredicate(predicate))
+	}
+	return joinSamples(samples, v.isToken)
+}
+
+// This private class method samples something that the specified assertion
+// does not match.  Within a token this is a single character that the
+// assertion excludes.  Within a rule this is a single terminal whose text the
+// assertion does not match.
+func (v *sampler_) sampleInversion(assertion AssertionLike) string {
+	for attempt := 0; attempt < samplerClass.maximumAttempts; attempt++ {
+		if v.isToken {
+			var character = v.pickRune(samplerClass.alphabet)
+			if !v.matcher.matchesRune(assertion, character) {
+				return string(character)
+			}
+			continue
+		}
+		if len(v.terminals) == 0 {
+			break
+		}
+		var terminal = v.terminals[v.generator.Intn(len(v.terminals))]
+		var text = v.sampleTerminal(terminal.assertion)
+		if len(text) > 0 && !v.matchesText(assertion, text) {
+			return text
+		}
+	}
+	panic(fmt.Sprintf("Nothing could be found that does not match: %v",
+		describeAssertion(assertion)))
+}
+
+func (v *sampler_) sampleName(name string) string {
+	var definition, ok = v.definitions[name]
+	if !ok {
+		panic(fmt.Sprintf("The grammar is missing a definition for name: %v", name))
+	}
+	var isToken = v.isToken
+	v.isToken = !isRuleName(name)
+	v.depth++
+	var sample = v.sampleExpression(definition.GetExpression())
+	v.depth--
+	v.isToken = isToken
+	return sample
+}
+
+func (v *sampler_) samplePredicate(predicate PredicateLike) string {
+	var assertion = predicate.GetAssertion()
+	if predicate.IsInverted() {
+		return v.sampleInversion(assertion)
+	}
+	return v.sampleAssertion(assertion)
+}
+
+// This private class method samples the text of the specified terminal as a
+// token.
+func (v *sampler_) sampleTerminal(assertion AssertionLike) string {
+	var isToken = v.isToken
+	v.isToken = true
+	var sample = v.sampleAssertion(assertion)
+	v.isToken = isToken
+	return sample
+}
+
+// PRIVATE FUNCTIONS
+
+// This private function joins the specified samples.  Within a rule the samples
+// are separated by a space since spaces between terminals are ignored, whereas
+// within a token the spaces are significant.
+func joinSamples(samples []string, isToken bool) string {
+	if isToken {
+		return sts.Join(samples, "")
+	}
+	var nonempty []string
+	for _, sample := range samples {
+		if len(sample) > 0 {
+			nonempty = append(nonempty, sample)
+		}
+	}
+	return sts.Join(nonempty, " ")
+}
+
+// This private constant is the height of a definition that can never be
+// completed.
+const unreachable_ = 1 << 30
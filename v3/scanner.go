@@ -13,6 +13,7 @@ package cdsn
 import (
 	//fmt "fmt"
 	reg "regexp"
+	stc "strconv"
 	sts "strings"
 )
 
@@ -172,6 +173,7 @@ type scanner_ struct {
 	first    int // A zero based index of the first possible rune in the next token.
 	line     int // The line number in the document of the next rune.
 	next     int // A zero based index of the next possible rune in the next token.
+	offset   int // A zero based byte offset of the first possible rune in the next token.
 	position int // The position in the current line of the next rune.
 	runes    []rune
 	tokens   chan *token_
@@ -185,6 +187,7 @@ type scanner_ struct {
 // to the channel.
 func (v *scanner_) emitToken(tokenType string) string {
 	var tokenValue = string(v.runes[v.first:v.next])
+	var span = v.spanToken(tokenValue)
 	switch tokenValue {
 	case "\a":
 		tokenValue = "<BELL>"
@@ -201,10 +204,11 @@ func (v *scanner_) emitToken(tokenType string) string {
 	case "\v":
 		tokenValue = "<VTAB>"
 	}
-	var token = TokenClass().FromContext(v.line, v.position, tokenType, tokenValue)
+	var token = TokenClass().FromSpan(span, tokenType, tokenValue)
 	//fmt.Println(token) // Uncomment when debugging.
 	v.tokens <- token
 	v.position += v.next - v.first
+	v.offset = span.GetEnd().GetOffset()
 	v.first = v.next
 	return tokenType
 }
@@ -323,6 +327,7 @@ func (v *scanner_) foundSpace() bool {
 	if len(matches) > 0 {
 		v.next += len([]rune(matches[0]))
 		v.position += v.next - v.first
+		v.offset += len(matches[0])
 		v.first = v.next
 		// Don't pass spaces along to the parser.
 		return true
@@ -341,6 +346,23 @@ func (v *scanner_) foundSymbol() bool {
 	return false
 }
 
+// This private class method returns the span of source text covered by the
+// specified raw token text which starts at the current scanner location.
+func (v *scanner_) spanToken(text string) SpanLike {
+	var start = LocationClass().FromPosition(v.line, v.position, v.offset)
+	var line = v.line
+	var position = v.position
+	for _, character := range text {
+		position++
+		if character == '\n' {
+			line++
+			position = 1
+		}
+	}
+	var end = LocationClass().FromPosition(line, position, v.offset+len(text))
+	return SpanClass().FromLocations(start, end)
+}
+
 func (v *scanner_) scanTokens() {
 loop:
 	for v.next < len(v.runes) {
@@ -392,3 +414,66 @@ const (
 	unicode_   = `x` + base16_ + `{2}|u` + base16_ + `{4}|U` + base16_ + `{8}`
 	upper_     = `\p{Lu}`
 )
+
+// PRIVATE FUNCTIONS
+
+// This private function returns the rune denoted by the specified character
+// token (e.g. 'a').
+func unquoteCharacter(character string) rune {
+	var runes = []rune(character)
+	return runes[1]
+}
+
+// This private function returns the string denoted by the specified literal
+// token (e.g. "a\tb") with all escape sequences replaced by the runes they
+// represent.
+func unquoteLiteral(literal string) string {
+	var runes = []rune(literal)
+	runes = runes[1 : len(runes)-1]
+	var builder sts.Builder
+	for index := 0; index < len(runes); index++ {
+		var character = runes[index]
+		if character != '\\' || index+1 == len(runes) {
+			builder.WriteRune(character)
+			continue
+		}
+		index++
+		character = runes[index]
+		var digits int
+		switch character {
+		case 'a':
+			builder.WriteRune('\a')
+		case 'b':
+			builder.WriteRune('\b')
+		case 'f':
+			builder.WriteRune('\f')
+		case 'n':
+			builder.WriteRune('\n')
+		case 'r':
+			builder.WriteRune('\r')
+		case 't':
+			builder.WriteRune('\t')
+		case 'v':
+			builder.WriteRune('\v')
+		case 'x':
+			digits = 2
+		case 'u':
+			digits = 4
+		case 'U':
+			digits = 8
+		default:
+			builder.WriteRune(character)
+		}
+		if digits > 0 && index+digits < len(runes) {
+			var hex = string(runes[index+1 : index+1+digits])
+			var value, err = stc.ParseUint(hex, 16, 32)
+			if err == nil {
+				builder.WriteRune(rune(value))
+				index += digits
+			} else {
+				builder.WriteRune(character)
+			}
+		}
+	}
+	return builder.String()
+}
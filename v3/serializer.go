@@ -0,0 +1,424 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	jsn "encoding/json"
+	fmt "fmt"
+	col "github.com/craterdog/go-collection-framework/v3"
+	yml "gopkg.in/yaml.v3"
+	sts "strings"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type serializerClass_ struct {
+	json_ string
+	yaml_ string
+}
+
+// Private Class Namespace Reference
+
+var serializerClass = &serializerClass_{
+	json_: "JSON",
+	yaml_: "YAML",
+}
+
+// Public Class Namespace Access
+
+func SerializerClass() SerializerClassLike {
+	return serializerClass
+}
+
+// Public Class Constants
+
+func (c *serializerClass_) GetJSON() string {
+	return c.json_
+}
+
+func (c *serializerClass_) GetYAML() string {
+	return c.yaml_
+}
+
+// Public Class Constructors
+
+func (c *serializerClass_) FromFormat(format string) SerializerLike {
+	if format != c.json_ && format != c.yaml_ {
+		panic(fmt.Sprintf("The format is not supported: %v", format))
+	}
+	var serializer = &serializer_{
+		format: format,
+	}
+	return serializer
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type serializer_ struct {
+	format string
+}
+
+// Public Interface
+
+func (v *serializer_) DeserializeDocument(source string) (
+	document DocumentLike,
+	err error,
+) {
+	defer func() {
+		if e := recover(); e != nil {
+			var message, ok = e.(string)
+			if !ok {
+				panic(e)
+			}
+			document = nil
+			err = fmt.Errorf("The %v document cannot be deserialized: %v",
+				v.format, message)
+		}
+	}()
+
+	// Decode the records, rejecting any unknown fields.
+	var record documentRecord_
+	var reader = sts.NewReader(source)
+	if v.format == serializerClass.json_ {
+		var decoder = jsn.NewDecoder(reader)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&record)
+	} else {
+		var decoder = yml.NewDecoder(reader)
+		decoder.KnownFields(true)
+		err = decoder.Decode(&record)
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// Rebuild the parse tree from the records.
+	document = v.decodeDocument(&record)
+	return document, nil
+}
+
+func (v *serializer_) GetFormat() string {
+	return v.format
+}
+
+func (v *serializer_) SerializeDocument(document DocumentLike) string {
+	var record = v.encodeDocument(document)
+	var builder sts.Builder
+	var err error
+	if v.format == serializerClass.json_ {
+		var encoder = jsn.NewEncoder(&builder)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "    ")
+		err = encoder.Encode(record)
+	} else {
+		var encoder = yml.NewEncoder(&builder)
+		encoder.SetIndent(4)
+		err = encoder.Encode(record)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("The document could not be serialized: %v", err))
+	}
+	return builder.String()
+}
+
+// Private Interface
+
+func (v *serializer_) decodeAlternative(record *alternativeRecord_) AlternativeLike {
+	if record == nil || len(record.Factors) == 0 {
+		panic("An alternative requires at least one factor.")
+	}
+	var factors = col.ListClass[FactorLike]().Empty()
+	for _, factor := range record.Factors {
+		factors.AppendValue(v.decodeFactor(factor))
+	}
+	var alternative = AlternativeClass().FromFactors(factors)
+	if len(record.Note) > 0 {
+		alternative.SetNote(record.Note)
+	}
+	return alternative
+}
+
+// This private class method returns the assertion for the specified record,
+// which must contain exactly one of an element, glyph or precedence.
+func (v *serializer_) decodeAssertion(record *assertionRecord_) AssertionLike {
+	if record == nil {
+		panic("A predicate requires an assertion.")
+	}
+	switch {
+	case record.Element != nil && record.Glyph == nil && record.Precedence == nil:
+		return AssertionClass().FromElement(v.decodeElement(record.Element))
+	case record.Element == nil && record.Glyph != nil && record.Precedence == nil:
+		return AssertionClass().FromGlyph(v.decodeGlyph(record.Glyph))
+	case record.Element == nil && record.Glyph == nil && record.Precedence != nil:
+		var expression = v.decodeExpression(record.Precedence.Expression)
+		return AssertionClass().FromPrecedence(PrecedenceClass().FromExpression(expression))
+	default:
+		panic("An assertion requires exactly one element, glyph or precedence.")
+	}
+}
+
+func (v *serializer_) decodeDefinition(record *definitionRecord_) DefinitionLike {
+	if !sts.HasPrefix(record.Symbol, "$") {
+		panic(fmt.Sprintf("A definition requires a symbol: %q", record.Symbol))
+	}
+	var expression = v.decodeExpression(record.Expression)
+	return DefinitionClass().FromSymbolAndExpression(record.Symbol, expression)
+}
+
+func (v *serializer_) decodeDocument(record *documentRecord_) DocumentLike {
+	if record.Grammar == nil || len(record.Grammar.Statements) == 0 {
+		panic("A document requires a grammar with at least one statement.")
+	}
+	var statements = col.ListClass[StatementLike]().Empty()
+	for _, statement := range record.Grammar.Statements {
+		statements.AppendValue(v.decodeStatement(statement))
+	}
+	return DocumentClass().FromGrammar(GrammarClass().FromStatements(statements))
+}
+
+func (v *serializer_) decodeElement(record *elementRecord_) ElementLike {
+	switch {
+	case len(record.Intrinsic) > 0 && len(record.Literal)+len(record.Name) == 0:
+		return ElementClass().FromIntrinsic(record.Intrinsic)
+	case len(record.Literal) > 0 && len(record.Intrinsic)+len(record.Name) == 0:
+		return ElementClass().FromLiteral(record.Literal)
+	case len(record.Name) > 0 && len(record.Intrinsic)+len(record.Literal) == 0:
+		return ElementClass().FromName(record.Name)
+	default:
+		panic("An element requires exactly one intrinsic, literal or name.")
+	}
+}
+
+func (v *serializer_) decodeExpression(record *expressionRecord_) ExpressionLike {
+	if record == nil || len(record.Alternatives) == 0 {
+		panic("An expression requires at least one alternative.")
+	}
+	var alternatives = col.ListClass[AlternativeLike]().Empty()
+	for _, alternative := range record.Alternatives {
+		alternatives.AppendValue(v.decodeAlternative(alternative))
+	}
+	var expression = ExpressionClass().FromAlternatives(alternatives)
+	expression.SetMultilined(record.Multilined)
+	return expression
+}
+
+func (v *serializer_) decodeFactor(record *factorRecord_) FactorLike {
+	if record == nil || record.Predicate == nil {
+		panic("A factor requires a predicate.")
+	}
+	var assertion = v.decodeAssertion(record.Predicate.Assertion)
+	var predicate = PredicateClass().FromAssertion(assertion, record.Predicate.Inverted)
+	var factor = FactorClass().FromPredicate(predicate)
+	if record.Cardinality != nil {
+		var constraint = record.Cardinality.Constraint
+		if constraint == nil {
+			panic("A cardinality requires a constraint.")
+		}
+		var cardinality = CardinalityClass().FromConstraint(
+			ConstraintClass().FromRange(constraint.First, constraint.Last),
+		)
+		factor.SetCardinality(cardinality)
+	}
+	return factor
+}
+
+func (v *serializer_) decodeGlyph(record *glyphRecord_) GlyphLike {
+	if len(record.Last) > 0 {
+		return GlyphClass().FromRange(record.First, record.Last)
+	}
+	return GlyphClass().FromCharacter(record.First)
+}
+
+func (v *serializer_) decodeStatement(record *statementRecord_) StatementLike {
+	if record == nil {
+		panic("A statement requires exactly one comment or definition.")
+	}
+	switch {
+	case len(record.Comment) > 0 && record.Definition == nil:
+		return StatementClass().FromComment(record.Comment)
+	case len(record.Comment) == 0 && record.Definition != nil:
+		return StatementClass().FromDefinition(v.decodeDefinition(record.Definition))
+	default:
+		panic("A statement requires exactly one comment or definition.")
+	}
+}
+
+func (v *serializer_) encodeAlternative(alternative AlternativeLike) *alternativeRecord_ {
+	var record = &alternativeRecord_{
+		Note: alternative.GetNote(),
+	}
+	var iterator = alternative.GetFactors().GetIterator()
+	for iterator.HasNext() {
+		record.Factors = append(record.Factors, v.encodeFactor(iterator.GetNext()))
+	}
+	return record
+}
+
+func (v *serializer_) encodeAssertion(assertion AssertionLike) *assertionRecord_ {
+	var record = &assertionRecord_{}
+	var element = assertion.GetElement()
+	var glyph = assertion.GetGlyph()
+	var precedence = assertion.GetPrecedence()
+	switch {
+	case element != nil:
+		record.Element = &elementRecord_{
+			Intrinsic: element.GetIntrinsic(),
+			Literal:   element.GetLiteral(),
+			Name:      element.GetName(),
+		}
+	case glyph != nil:
+		record.Glyph = &glyphRecord_{
+			First: glyph.GetFirst(),
+			Last:  glyph.GetLast(),
+		}
+	case precedence != nil:
+		record.Precedence = &precedenceRecord_{
+			Expression: v.encodeExpression(precedence.GetExpression()),
+		}
+	}
+	return record
+}
+
+func (v *serializer_) encodeDocument(document DocumentLike) *documentRecord_ {
+	var grammar = &grammarRecord_{}
+	var iterator = document.GetGrammar().GetStatements().GetIterator()
+	for iterator.HasNext() {
+		var statement = iterator.GetNext()
+		var record = &statementRecord_{
+			Comment: statement.GetComment(),
+		}
+		var definition = statement.GetDefinition()
+		if definition != nil {
+			record.Definition = &definitionRecord_{
+				Expression: v.encodeExpression(definition.GetExpression()),
+				Symbol:     definition.GetSymbol(),
+			}
+		}
+		grammar.Statements = append(grammar.Statements, record)
+	}
+	var record = &documentRecord_{
+		Grammar: grammar,
+	}
+	return record
+}
+
+func (v *serializer_) encodeExpression(expression ExpressionLike) *expressionRecord_ {
+	var record = &expressionRecord_{
+		Multilined: expression.IsMultilined(),
+	}
+	var iterator = expression.GetAlternatives().GetIterator()
+	for iterator.HasNext() {
+		record.Alternatives = append(record.Alternatives, v.encodeAlternative(iterator.GetNext()))
+	}
+	return record
+}
+
+func (v *serializer_) encodeFactor(factor FactorLike) *factorRecord_ {
+	var predicate = factor.GetPredicate()
+	var record = &factorRecord_{
+		Predicate: &predicateRecord_{
+			Assertion: v.encodeAssertion(predicate.GetAssertion()),
+			Inverted:  predicate.IsInverted(),
+		},
+	}
+	var cardinality = factor.GetCardinality()
+	if cardinality != nil {
+		var constraint = cardinality.GetConstraint()
+		record.Cardinality = &cardinalityRecord_{
+			Constraint: &constraintRecord_{
+				First: constraint.GetFirst(),
+				Last:  constraint.GetLast(),
+			},
+		}
+	}
+	return record
+}
+
+// PRIVATE TYPES
+
+// These private types define the records into which a document is encoded.
+// Each record has a field for each attribute of the corresponding node in the
+// parse tree, named after the getter method for the attribute.  The source
+// spans are not encoded since they describe the source text rather than the
+// grammar itself.  Empty attributes are omitted except for the last number in
+// a constraint, which is empty when the number of repetitions is unlimited.
+
+type alternativeRecord_ struct {
+	Factors []*factorRecord_ `json:"factors" yaml:"factors"`
+	Note    string           `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+type assertionRecord_ struct {
+	Element    *elementRecord_    `json:"element,omitempty" yaml:"element,omitempty"`
+	Glyph      *glyphRecord_      `json:"glyph,omitempty" yaml:"glyph,omitempty"`
+	Precedence *precedenceRecord_ `json:"precedence,omitempty" yaml:"precedence,omitempty"`
+}
+
+type cardinalityRecord_ struct {
+	Constraint *constraintRecord_ `json:"constraint" yaml:"constraint"`
+}
+
+type constraintRecord_ struct {
+	First string `json:"first" yaml:"first"`
+	Last  string `json:"last" yaml:"last"`
+}
+
+type definitionRecord_ struct {
+	Expression *expressionRecord_ `json:"expression" yaml:"expression"`
+	Symbol     string             `json:"symbol" yaml:"symbol"`
+}
+
+type documentRecord_ struct {
+	Grammar *grammarRecord_ `json:"grammar" yaml:"grammar"`
+}
+
+type elementRecord_ struct {
+	Intrinsic string `json:"intrinsic,omitempty" yaml:"intrinsic,omitempty"`
+	Literal   string `json:"literal,omitempty" yaml:"literal,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+type expressionRecord_ struct {
+	Alternatives []*alternativeRecord_ `json:"alternatives" yaml:"alternatives"`
+	Multilined   bool                  `json:"multilined,omitempty" yaml:"multilined,omitempty"`
+}
+
+type factorRecord_ struct {
+	Cardinality *cardinalityRecord_ `json:"cardinality,omitempty" yaml:"cardinality,omitempty"`
+	Predicate   *predicateRecord_   `json:"predicate" yaml:"predicate"`
+}
+
+type glyphRecord_ struct {
+	First string `json:"first" yaml:"first"`
+	Last  string `json:"last,omitempty" yaml:"last,omitempty"`
+}
+
+type grammarRecord_ struct {
+	Statements []*statementRecord_ `json:"statements" yaml:"statements"`
+}
+
+type precedenceRecord_ struct {
+	Expression *expressionRecord_ `json:"expression" yaml:"expression"`
+}
+
+type predicateRecord_ struct {
+	Assertion *assertionRecord_ `json:"assertion" yaml:"assertion"`
+	Inverted  bool              `json:"inverted,omitempty" yaml:"inverted,omitempty"`
+}
+
+type statementRecord_ struct {
+	Comment    string             `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Definition *definitionRecord_ `json:"definition,omitempty" yaml:"definition,omitempty"`
+}
@@ -0,0 +1,71 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+import (
+	fmt "fmt"
+)
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type spanClass_ struct {
+	// This class does not define any constants.
+}
+
+// Private Class Namespace Reference
+
+var spanClass = &spanClass_{
+	// This class does not initialize any constants.
+}
+
+// Public Class Namespace Access
+
+func SpanClass() SpanClassLike {
+	return spanClass
+}
+
+// Public Class Constructors
+
+func (c *spanClass_) FromLocations(start, end LocationLike) SpanLike {
+	if start == nil || end == nil {
+		panic("A span requires both a start and an end location.")
+	}
+	var span = &span_{
+		end:   end,
+		start: start,
+	}
+	return span
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type span_ struct {
+	end   LocationLike // The location just past the last rune in the span.
+	start LocationLike // The location of the first rune in the span.
+}
+
+// Public Interface
+
+func (v *span_) GetEnd() LocationLike {
+	return v.end
+}
+
+func (v *span_) GetStart() LocationLike {
+	return v.start
+}
+
+func (v *span_) String() string {
+	return fmt.Sprintf("%v-%v", v.start, v.end)
+}
@@ -59,6 +59,7 @@ func (c *statementClass_) FromDefinition(definition DefinitionLike) StatementLik
 type statement_ struct {
 	comment    string
 	definition DefinitionLike
+	span       SpanLike
 }
 
 // Public Interface
@@ -71,6 +72,10 @@ func (v *statement_) GetDefinition() DefinitionLike {
 	return v.definition
 }
 
+func (v *statement_) GetSpan() SpanLike {
+	return v.span
+}
+
 func (v *statement_) SetComment(comment string) {
 	if len(comment) < 4 {
 		var message = fmt.Sprintf(
@@ -90,3 +95,7 @@ func (v *statement_) SetDefinition(definition DefinitionLike) {
 	v.comment = ""
 	v.definition = definition
 }
+
+func (v *statement_) SetSpan(span SpanLike) {
+	v.span = span
+}
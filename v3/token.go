@@ -123,13 +123,30 @@ func (c *tokenClass_) FromContext(
 	return token
 }
 
+func (c *tokenClass_) FromSpan(
+	span SpanLike,
+	tokenType string,
+	tokenValue string,
+) *token_ {
+	var start = span.GetStart()
+	var token = &token_{
+		line_:     start.GetLine(),
+		position_: start.GetPosition(),
+		span_:     span,
+		type_:     tokenType,
+		value_:    tokenValue,
+	}
+	return token
+}
+
 // CLASS INSTANCES
 
 // Private Class Type Definition
 
 type token_ struct {
-	line_     int // The line number of the token in the lexical context.
-	position_ int // The position in the line of the first rune of the token.
+	line_     int      // The line number of the token in the lexical context.
+	position_ int      // The position in the line of the first rune of the token.
+	span_     SpanLike // The source text of the token, nil if unknown.
 	type_     string
 	value_    string
 }
@@ -144,6 +161,10 @@ func (v *token_) GetPosition() int {
 	return v.position_
 }
 
+func (v *token_) GetSpan() SpanLike {
+	return v.span_
+}
+
 func (v *token_) GetType() string {
 	return v.type_
 }
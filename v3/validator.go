@@ -14,6 +14,7 @@ import (
 	fmt "fmt"
 	col "github.com/craterdog/go-collection-framework/v3"
 	stc "strconv"
+	sts "strings"
 	uni "unicode"
 )
 
@@ -22,13 +23,13 @@ import (
 // Private Class Namespace Type
 
 type validatorClass_ struct {
-	// This class does not define any class constants.
+	startSymbol string
 }
 
 // Private Class Namespace Reference
 
 var validatorClass = &validatorClass_{
-	// This class does not initialize any class constants.
+	startSymbol: "$document",
 }
 
 // Public Class Namespace Access
@@ -44,43 +45,165 @@ func (c *validatorClass_) Default() ValidatorLike {
 	return validator
 }
 
+func (c *validatorClass_) Strict() ValidatorLike {
+	var validator = &validator_{
+		isStrict: true,
+	}
+	return validator
+}
+
 // CLASS INSTANCES
 
 // Private Class Type Definition
 
 type validator_ struct {
-	definitions col.StackLike[DefinitionLike]
+	definition  DefinitionLike
+	definitions col.CatalogLike[string, DefinitionLike]
+	diagnostics col.ListLike[DiagnosticLike] // Nil unless collecting diagnostics.
 	inInversion bool
+	isStrict    bool // Whether or not the rules must be LL(1).
 	isToken     bool
+	references  col.CatalogLike[string, col.ListLike[ElementLike]]
+	span        SpanLike // The location of the innermost node being validated.
 }
 
 // Public Interface
 
+func (v *validator_) DiagnoseDocument(document DocumentLike) col.Sequential[DiagnosticLike] {
+	v.diagnostics = col.ListClass[DiagnosticLike]().Empty()
+	defer func() {
+		v.diagnostics = nil
+	}()
+	v.validateDocument(document)
+	return v.diagnostics
+}
+
 func (v *validator_) ValidateDocument(document DocumentLike) {
-	v.definitions = col.StackClass[DefinitionLike]().Empty()
-	var grammar = document.GetGrammar()
-	v.validateGrammar(grammar)
+	v.validateDocument(document)
 }
 
 // Private Interface
 
+// This private class method records a reference to a name from within the
+// current definition.
+func (v *validator_) addReference(element ElementLike) {
+	if v.definition == nil {
+		return
+	}
+	var symbol = v.definition.GetSymbol()
+	var references = v.references.GetValue(symbol)
+	if references == nil {
+		references = col.ListClass[ElementLike]().Empty()
+		v.references.SetValue(symbol, references)
+	}
+	references.AppendValue(element)
+}
+
+// This private class method records the location of the node being validated
+// if it is known and returns the location of the enclosing node.
+func (v *validator_) enterNode(span SpanLike) SpanLike {
+	var enclosing = v.span
+	if span != nil {
+		v.span = span
+	}
+	return enclosing
+}
+
+// This private class method restores the location of the enclosing node.
+func (v *validator_) exitNode(enclosing SpanLike) {
+	v.span = enclosing
+}
+
+// This private class method returns the shortest path of token references that
+// leads from the specified token definition back to itself, or nil if there is
+// no such path.
+func (v *validator_) findTokenCycle(symbol string) []string {
+	var previous = col.CatalogClass[string, string]().Empty()
+	var pending = col.ListClass[string]().FromArray([]string{symbol})
+	for !pending.IsEmpty() {
+		var current = pending.RemoveValue(1)
+		var references = v.references.GetValue(current)
+		if references == nil {
+			continue
+		}
+		var iterator = references.GetIterator()
+		for iterator.HasNext() {
+			var referenced = "$" + iterator.GetNext().GetName()
+			if !uni.IsUpper([]rune(referenced)[1]) ||
+				v.definitions.GetValue(referenced) == nil ||
+				len(previous.GetValue(referenced)) > 0 {
+				continue
+			}
+			previous.SetValue(referenced, current)
+			if referenced == symbol {
+				// Walk the path backwards to recover the cycle.
+				var cycle = []string{symbol}
+				for current != symbol {
+					cycle = append([]string{current}, cycle...)
+					current = previous.GetValue(current)
+				}
+				return append([]string{symbol}, cycle...)
+			}
+			pending.AppendValue(referenced)
+		}
+	}
+	return nil
+}
+
 func (v *validator_) formatError(message string) string {
-	var definition = v.definitions.RemoveTop()
 	message = fmt.Sprintf(
 		"The definition for %v is invalid:\n%v\n",
-		definition.GetSymbol(),
+		v.definition.GetSymbol(),
 		message,
 	)
 	return message
 }
 
+// This private class method reports a problem of the specified kind with the
+// current definition.  If diagnostics are being collected the problem is added
+// to them and validation continues.  Otherwise the validator panics with a
+// formatted error message if the problem is an error, and ignores it if it is
+// only a warning.
+func (v *validator_) report(kind string, severity string, message string) {
+	var symbol string
+	if v.definition != nil {
+		symbol = v.definition.GetSymbol()
+	}
+	if v.diagnostics == nil {
+		if severity != DiagnosticClass().GetError() {
+			return
+		}
+		if len(symbol) == 0 {
+			// The problem is not within a definition.
+			panic(message)
+		}
+		panic(v.formatError(message))
+	}
+	var diagnostic = DiagnosticClass().FromKind(
+		kind,
+		severity,
+		symbol,
+		sts.TrimSpace(message),
+		v.span,
+	)
+	v.diagnostics.AppendValue(diagnostic)
+}
+
+// This private class method reports an invalid construct within the current
+// definition.
+func (v *validator_) reportError(message string) {
+	v.report(DiagnosticClass().GetInvalid(), DiagnosticClass().GetError(), message)
+}
+
 func (v *validator_) validateAlternative(alternative AlternativeLike) {
+	var enclosing = v.enterNode(alternative.GetSpan())
+	defer v.exitNode(enclosing)
 	var factors = alternative.GetFactors()
 	if factors == nil || factors.IsEmpty() {
-		var message = v.formatError(
+		v.reportError(
 			"Each alternative must have at least one factor.",
 		)
-		panic(message)
+		return
 	}
 	var iterator = factors.GetIterator()
 	for iterator.HasNext() {
@@ -93,7 +216,34 @@ func (v *validator_) validateAlternative(alternative AlternativeLike) {
 	}
 }
 
+// This private class method reports the problems found by analyzing the rule
+// definitions.  Left recursive rules are warnings unless the validator is
+// strict.  Choices that cannot be made using the next token are only reported,
+// as errors, by a strict validator.
+func (v *validator_) validateAnalysis(document DocumentLike) {
+	var analyzer = AnalyzerClass().FromDocument(document)
+	var iterator = analyzer.GetDiagnostics().GetIterator()
+	for iterator.HasNext() {
+		var diagnostic = iterator.GetNext()
+		var severity = diagnostic.GetSeverity()
+		if v.isStrict {
+			severity = DiagnosticClass().GetError()
+		}
+		v.validateDiagnostic(diagnostic, severity)
+	}
+	if v.isStrict {
+		iterator = analyzer.GetConflicts().GetIterator()
+		for iterator.HasNext() {
+			var diagnostic = iterator.GetNext()
+			v.validateDiagnostic(diagnostic, DiagnosticClass().GetError())
+		}
+	}
+	v.definition = nil
+}
+
 func (v *validator_) validateAssertion(assertion AssertionLike) {
+	var enclosing = v.enterNode(assertion.GetSpan())
+	defer v.exitNode(enclosing)
 	var element = assertion.GetElement()
 	var glyph = assertion.GetGlyph()
 	var precedence = assertion.GetPrecedence()
@@ -105,30 +255,30 @@ func (v *validator_) validateAssertion(assertion AssertionLike) {
 	case element == nil && glyph == nil && precedence != nil:
 		v.validatePrecedence(precedence)
 	default:
-		var message = v.formatError(
+		v.reportError(
 			"An assertion must contain exactly one element, glyph, or precedence.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateCharacter(character string) {
 	var matches = ScannerClass().MatchCharacter(character)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid character.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateCardinality(cardinality CardinalityLike) {
+	var enclosing = v.enterNode(cardinality.GetSpan())
+	defer v.exitNode(enclosing)
 	var constraint = cardinality.GetConstraint()
 	if constraint == nil {
-		var message = v.formatError(
+		v.reportError(
 			"A cardinality must have a constraint.",
 		)
-		panic(message)
+		return
 	}
 	v.validateConstraint(constraint)
 }
@@ -136,14 +286,15 @@ func (v *validator_) validateCardinality(cardinality CardinalityLike) {
 func (v *validator_) validateComment(comment string) {
 	var matches = ScannerClass().MatchComment(comment)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid comment.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateConstraint(constraint ConstraintLike) {
+	var enclosing = v.enterNode(constraint.GetSpan())
+	defer v.exitNode(enclosing)
 	var first = constraint.GetFirst()
 	v.validateNumber(first)
 	var last = constraint.GetLast()
@@ -152,31 +303,58 @@ func (v *validator_) validateConstraint(constraint ConstraintLike) {
 		var firstNumber, _ = stc.ParseInt(first, 10, 64)
 		var lastNumber, _ = stc.ParseInt(last, 10, 64)
 		if firstNumber > lastNumber {
-			var message = v.formatError(
+			v.reportError(
 				"The first number in a constraint cannot be greater than the last.",
 			)
-			panic(message)
 		}
 	}
 }
 
 func (v *validator_) validateDefinition(definition DefinitionLike) {
-	v.definitions.AddValue(definition)
+	var enclosing = v.enterNode(definition.GetSpan())
+	defer v.exitNode(enclosing)
+	v.definition = definition
 	var symbol = definition.GetSymbol()
 	v.validateSymbol(symbol)
+	if v.definitions.GetValue(symbol) == nil {
+		v.definitions.SetValue(symbol, definition)
+	}
 	var expression = definition.GetExpression()
 	if expression == nil {
-		var message = v.formatError(
+		v.reportError(
 			"A definition must contain an expression.",
 		)
-		panic(message)
+	} else {
+		v.validateExpression(expression)
 	}
-	v.validateExpression(expression)
 	v.inInversion = false
-	var _ = v.definitions.RemoveTop()
+	v.definition = nil
+}
+
+// This private class method reports the specified diagnostic from the analyzer
+// with the specified severity.
+func (v *validator_) validateDiagnostic(diagnostic DiagnosticLike, severity string) {
+	v.definition = v.definitions.GetValue(diagnostic.GetSymbol())
+	var enclosing = v.enterNode(diagnostic.GetSpan())
+	v.report(diagnostic.GetKind(), severity, diagnostic.GetMessage())
+	v.exitNode(enclosing)
+}
+
+func (v *validator_) validateDocument(document DocumentLike) {
+	v.definition = nil
+	v.definitions = col.CatalogClass[string, DefinitionLike]().Empty()
+	v.inInversion = false
+	v.references = col.CatalogClass[string, col.ListLike[ElementLike]]().Empty()
+	v.span = document.GetSpan()
+	var grammar = document.GetGrammar()
+	v.validateGrammar(grammar)
+	v.validateReferences()
+	v.validateAnalysis(document)
 }
 
 func (v *validator_) validateElement(element ElementLike) {
+	var enclosing = v.enterNode(element.GetSpan())
+	defer v.exitNode(enclosing)
 	var intrinsic = element.GetIntrinsic()
 	var name = element.GetName()
 	var literal = element.GetLiteral()
@@ -185,23 +363,25 @@ func (v *validator_) validateElement(element ElementLike) {
 		v.validateIntrinsic(intrinsic)
 	case len(intrinsic) == 0 && len(name) > 0 && len(literal) == 0:
 		v.validateName(name)
+		v.addReference(element)
 	case len(intrinsic) == 0 && len(name) == 0 && len(literal) > 0:
 		v.validateLiteral(literal)
 	default:
-		var message = v.formatError(
+		v.reportError(
 			"An element must contain exactly one intrinsic, name, or literal.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateExpression(expression ExpressionLike) {
+	var enclosing = v.enterNode(expression.GetSpan())
+	defer v.exitNode(enclosing)
 	var alternatives = expression.GetAlternatives()
 	if alternatives == nil || alternatives.IsEmpty() {
-		var message = v.formatError(
+		v.reportError(
 			"Each expression must have at least one alternative.",
 		)
-		panic(message)
+		return
 	}
 	var iterator = alternatives.GetIterator()
 	for iterator.HasNext() {
@@ -211,14 +391,16 @@ func (v *validator_) validateExpression(expression ExpressionLike) {
 }
 
 func (v *validator_) validateFactor(factor FactorLike) {
+	var enclosing = v.enterNode(factor.GetSpan())
+	defer v.exitNode(enclosing)
 	var predicate = factor.GetPredicate()
 	if predicate == nil {
-		var message = v.formatError(
+		v.reportError(
 			"A factor must contain a predicate.",
 		)
-		panic(message)
+	} else {
+		v.validatePredicate(predicate)
 	}
-	v.validatePredicate(predicate)
 	var cardinality = factor.GetCardinality()
 	if cardinality != nil {
 		v.validateCardinality(cardinality)
@@ -226,25 +408,30 @@ func (v *validator_) validateFactor(factor FactorLike) {
 }
 
 func (v *validator_) validateGlyph(glyph GlyphLike) {
+	var enclosing = v.enterNode(glyph.GetSpan())
+	defer v.exitNode(enclosing)
 	var first = glyph.GetFirst()
 	v.validateCharacter(first)
 	var last = glyph.GetLast()
 	if len(last) > 0 {
 		v.validateCharacter(last)
 		if first > last {
-			var message = v.formatError(
+			v.reportError(
 				"The first character in a glyph cannot come later than the last.",
 			)
-			panic(message)
 		}
 	}
 }
 
 func (v *validator_) validateGrammar(grammar GrammarLike) {
+	var enclosing = v.enterNode(grammar.GetSpan())
+	defer v.exitNode(enclosing)
 	var statements = grammar.GetStatements()
 	if statements == nil || statements.IsEmpty() {
-		var message = "The grammar must contain at least one statement.\n"
-		panic(message)
+		v.reportError(
+			"The grammar must contain at least one statement.",
+		)
+		return
 	}
 	var iterator = statements.GetIterator()
 	for iterator.HasNext() {
@@ -256,49 +443,43 @@ func (v *validator_) validateGrammar(grammar GrammarLike) {
 func (v *validator_) validateIntrinsic(intrinsic string) {
 	var matches = ScannerClass().MatchIntrinsic(intrinsic)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid intrinsic.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateLiteral(literal string) {
 	var matches = ScannerClass().MatchLiteral(literal)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid literal.",
 		)
-		panic(message)
 	}
 	if v.inInversion && len([]rune(literal)) > 3 {
-		var message = v.formatError(
+		v.reportError(
 			"A multi-character literal is not allowed in an inversion.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateName(name string) {
 	var matches = ScannerClass().MatchName(name)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid name.",
 		)
-		panic(message)
 	}
 	if uni.IsLower([]rune(name)[0]) {
 		if v.isToken {
-			var message = v.formatError(
+			v.reportError(
 				"A token definition cannot contain a rule name.",
 			)
-			panic(message)
 		}
 		if v.inInversion {
-			var message = v.formatError(
+			v.reportError(
 				"An inverted assertion cannot contain a rule name.",
 			)
-			panic(message)
 		}
 	}
 }
@@ -306,63 +487,169 @@ func (v *validator_) validateName(name string) {
 func (v *validator_) validateNote(note string) {
 	var matches = ScannerClass().MatchNote(note)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid note.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validateNumber(number string) {
 	var matches = ScannerClass().MatchNumber(number)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.reportError(
 			"Found an invalid number.",
 		)
-		panic(message)
 	}
 }
 
 func (v *validator_) validatePrecedence(precedence PrecedenceLike) {
+	var enclosing = v.enterNode(precedence.GetSpan())
+	defer v.exitNode(enclosing)
 	var expression = precedence.GetExpression()
-	if precedence == nil {
-		var message = v.formatError(
+	if expression == nil {
+		v.reportError(
 			"A precedence must contain an expression.",
 		)
-		panic(message)
+		return
 	}
 	v.validateExpression(expression)
 }
 
 func (v *validator_) validatePredicate(predicate PredicateLike) {
+	var enclosing = v.enterNode(predicate.GetSpan())
+	defer v.exitNode(enclosing)
 	var isInverted = predicate.IsInverted()
 	if isInverted && v.inInversion {
-		var message = v.formatError(
+		v.reportError(
 			"Inverted assertions cannot be nested.",
 		)
-		panic(message)
 	}
 	if isInverted {
 		v.inInversion = true
 	}
 	var assertion = predicate.GetAssertion()
 	if assertion == nil {
-		var message = v.formatError(
+		v.reportError(
 			"A predicate must have an assertion.",
 		)
-		panic(message)
+	} else {
+		v.validateAssertion(assertion)
+	}
+}
+
+// This private class method validates the references between the definitions.
+// Each referenced name must be defined.  If the grammar defines the start
+// symbol, each definition must also be referenced by another definition and be
+// reachable from the start symbol.
+func (v *validator_) validateReferences() {
+	// Report the first reference to each undefined name.
+	var undefined = col.CatalogClass[string, bool]().Empty()
+	var symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		var references = v.references.GetValue(symbol)
+		if references == nil {
+			continue
+		}
+		var iterator = references.GetIterator()
+		for iterator.HasNext() {
+			var element = iterator.GetNext()
+			var name = element.GetName()
+			if v.definitions.GetValue("$"+name) != nil || undefined.GetValue(name) {
+				continue
+			}
+			undefined.SetValue(name, true)
+			v.definition = v.definitions.GetValue(symbol)
+			var enclosing = v.enterNode(element.GetSpan())
+			v.report(
+				DiagnosticClass().GetUndefined(),
+				DiagnosticClass().GetError(),
+				"The grammar is missing a definition for name: "+name,
+			)
+			v.exitNode(enclosing)
+		}
+	}
+	v.definition = nil
+	v.validateTokenCycles()
+	var start = validatorClass.startSymbol
+	if v.definitions.GetValue(start) == nil {
+		return
 	}
-	v.validateAssertion(assertion)
+
+	// Determine which definitions are used and which can be reached.
+	var used = col.CatalogClass[string, bool]().Empty()
+	symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		var references = v.references.GetValue(symbol)
+		if references == nil {
+			continue
+		}
+		var iterator = references.GetIterator()
+		for iterator.HasNext() {
+			var referenced = "$" + iterator.GetNext().GetName()
+			if referenced != symbol {
+				used.SetValue(referenced, true)
+			}
+		}
+	}
+	var reachable = col.CatalogClass[string, bool]().Empty()
+	var pending = col.ListClass[string]().FromArray([]string{start})
+	for !pending.IsEmpty() {
+		var symbol = pending.RemoveValue(1)
+		if reachable.GetValue(symbol) || v.definitions.GetValue(symbol) == nil {
+			continue
+		}
+		reachable.SetValue(symbol, true)
+		var references = v.references.GetValue(symbol)
+		if references == nil {
+			continue
+		}
+		var iterator = references.GetIterator()
+		for iterator.HasNext() {
+			pending.AppendValue("$" + iterator.GetNext().GetName())
+		}
+	}
+
+	// Report the definitions that are unused or unreachable.
+	symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		v.definition = v.definitions.GetValue(symbol)
+		var enclosing = v.enterNode(v.definition.GetSpan())
+		switch {
+		case symbol == start:
+		case !used.GetValue(symbol):
+			v.report(
+				DiagnosticClass().GetUnused(),
+				DiagnosticClass().GetWarning(),
+				"The definition for "+symbol+" is never used.",
+			)
+		case !reachable.GetValue(symbol):
+			v.report(
+				DiagnosticClass().GetUnreachable(),
+				DiagnosticClass().GetWarning(),
+				"The definition for "+symbol+" cannot be reached from "+start+".",
+			)
+		}
+		v.exitNode(enclosing)
+	}
+	v.definition = nil
 }
 
 func (v *validator_) validateStatement(statement StatementLike) {
+	var enclosing = v.enterNode(statement.GetSpan())
+	defer v.exitNode(enclosing)
 	var comment = statement.GetComment()
 	if len(comment) > 0 {
 		v.validateComment(comment)
 	} else {
 		var definition = statement.GetDefinition()
 		if definition == nil {
-			panic("A statement must contain either a comment or a definition.")
+			v.reportError(
+				"A statement must contain either a comment or a definition.",
+			)
+			return
 		}
 		v.validateDefinition(definition)
 	}
@@ -371,10 +658,41 @@ func (v *validator_) validateStatement(statement StatementLike) {
 func (v *validator_) validateSymbol(symbol string) {
 	var matches = ScannerClass().MatchSymbol(symbol)
 	if len(matches) == 0 {
-		var message = v.formatError(
+		v.isToken = false
+		v.reportError(
 			"Found an invalid symbol.",
 		)
-		panic(message)
+		return
 	}
 	v.isToken = uni.IsUpper([]rune(matches[1])[0])
 }
+
+// This private class method reports each cycle of token definitions that
+// reference each other since token definitions cannot be recursive.  Each
+// cycle is reported once, on the first of its definitions.
+func (v *validator_) validateTokenCycles() {
+	var reported = col.CatalogClass[string, bool]().Empty()
+	var symbols = v.definitions.GetKeys().GetIterator()
+	for symbols.HasNext() {
+		var symbol = symbols.GetNext()
+		if !uni.IsUpper([]rune(symbol)[1]) || reported.GetValue(symbol) {
+			continue
+		}
+		var cycle = v.findTokenCycle(symbol)
+		if cycle == nil {
+			continue
+		}
+		for _, member := range cycle {
+			reported.SetValue(member, true)
+		}
+		v.definition = v.definitions.GetValue(symbol)
+		var enclosing = v.enterNode(v.definition.GetSpan())
+		v.report(
+			DiagnosticClass().GetRecursive(),
+			DiagnosticClass().GetError(),
+			"A token definition cannot be recursive: "+sts.Join(cycle, " → "),
+		)
+		v.exitNode(enclosing)
+	}
+	v.definition = nil
+}
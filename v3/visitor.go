@@ -0,0 +1,146 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type visitorClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var visitorClass = &visitorClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func VisitorClass() VisitorClassLike {
+	return visitorClass
+}
+
+// Public Class Constructors
+
+func (c *visitorClass_) Default() VisitorLike {
+	var visitor = &visitor_{}
+	return visitor
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type visitor_ struct {
+	// This type does not define any attributes.
+}
+
+// Public Interface
+
+func (v *visitor_) PostAlternative(alternative AlternativeLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostAssertion(assertion AssertionLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostCardinality(cardinality CardinalityLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostConstraint(constraint ConstraintLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostDefinition(definition DefinitionLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostDocument(document DocumentLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostElement(element ElementLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostExpression(expression ExpressionLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostFactor(factor FactorLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostGlyph(glyph GlyphLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostGrammar(grammar GrammarLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostPrecedence(precedence PrecedenceLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostPredicate(predicate PredicateLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PostStatement(statement StatementLike, walker WalkerLike) {
+}
+
+func (v *visitor_) PreAlternative(alternative AlternativeLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreAssertion(assertion AssertionLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreCardinality(cardinality CardinalityLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreConstraint(constraint ConstraintLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreDefinition(definition DefinitionLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreDocument(document DocumentLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreElement(element ElementLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreExpression(expression ExpressionLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreFactor(factor FactorLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreGlyph(glyph GlyphLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreGrammar(grammar GrammarLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PrePrecedence(precedence PrecedenceLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PrePredicate(predicate PredicateLike, walker WalkerLike) bool {
+	return true
+}
+
+func (v *visitor_) PreStatement(statement StatementLike, walker WalkerLike) bool {
+	return true
+}
@@ -0,0 +1,242 @@
+/*******************************************************************************
+ *   Copyright (c) 2009-2024 Crater Dog Technologies™.  All Rights Reserved.   *
+ *******************************************************************************
+ * DO NOT ALTER OR REMOVE COPYRIGHT NOTICES OR THIS FILE HEADER.               *
+ *                                                                             *
+ * This code is free software; you can redistribute it and/or modify it under  *
+ * the terms of The MIT License (MIT), as published by the Open Source         *
+ * Initiative. (See http://opensource.org/licenses/MIT)                        *
+ *******************************************************************************/
+
+package cdsn
+
+// CLASS NAMESPACE
+
+// Private Class Namespace Type
+
+type walkerClass_ struct {
+	// This class does not define any class constants.
+}
+
+// Private Class Namespace Reference
+
+var walkerClass = &walkerClass_{
+	// This class does not initialize any class constants.
+}
+
+// Public Class Namespace Access
+
+func WalkerClass() WalkerClassLike {
+	return walkerClass
+}
+
+// Public Class Constructors
+
+func (c *walkerClass_) FromVisitor(visitor VisitorLike) WalkerLike {
+	var walker = &walker_{
+		visitor: visitor,
+	}
+	return walker
+}
+
+// CLASS INSTANCES
+
+// Private Class Type Definition
+
+type walker_ struct {
+	definition DefinitionLike // The enclosing definition, nil if there is none.
+	depth      int            // The depth of the node being visited.
+	visitor    VisitorLike
+}
+
+// Public Interface
+
+func (v *walker_) GetDefinition() DefinitionLike {
+	return v.definition
+}
+
+func (v *walker_) GetDepth() int {
+	return v.depth
+}
+
+func (v *walker_) WalkDefinition(definition DefinitionLike) {
+	v.definition = nil
+	v.depth = 0
+	v.walkDefinition(definition)
+}
+
+func (v *walker_) WalkDocument(document DocumentLike) {
+	v.definition = nil
+	v.depth = 0
+	v.walkDocument(document)
+}
+
+func (v *walker_) WalkExpression(expression ExpressionLike) {
+	v.definition = nil
+	v.depth = 0
+	v.walkExpression(expression)
+}
+
+// Private Interface
+
+func (v *walker_) walkAlternative(alternative AlternativeLike) {
+	if alternative == nil || !v.visitor.PreAlternative(alternative, v) {
+		return
+	}
+	v.depth++
+	var factors = alternative.GetFactors()
+	if factors != nil {
+		var iterator = factors.GetIterator()
+		for iterator.HasNext() {
+			var factor = iterator.GetNext()
+			v.walkFactor(factor)
+		}
+	}
+	v.depth--
+	v.visitor.PostAlternative(alternative, v)
+}
+
+func (v *walker_) walkAssertion(assertion AssertionLike) {
+	if assertion == nil || !v.visitor.PreAssertion(assertion, v) {
+		return
+	}
+	v.depth++
+	v.walkElement(assertion.GetElement())
+	v.walkGlyph(assertion.GetGlyph())
+	v.walkPrecedence(assertion.GetPrecedence())
+	v.depth--
+	v.visitor.PostAssertion(assertion, v)
+}
+
+func (v *walker_) walkCardinality(cardinality CardinalityLike) {
+	if cardinality == nil || !v.visitor.PreCardinality(cardinality, v) {
+		return
+	}
+	v.depth++
+	v.walkConstraint(cardinality.GetConstraint())
+	v.depth--
+	v.visitor.PostCardinality(cardinality, v)
+}
+
+func (v *walker_) walkConstraint(constraint ConstraintLike) {
+	if constraint == nil || !v.visitor.PreConstraint(constraint, v) {
+		return
+	}
+	v.visitor.PostConstraint(constraint, v)
+}
+
+// This private class method walks the specified definition, which becomes the
+// enclosing definition for all of the nodes within it.
+func (v *walker_) walkDefinition(definition DefinitionLike) {
+	if definition == nil {
+		return
+	}
+	var enclosing = v.definition
+	v.definition = definition
+	if v.visitor.PreDefinition(definition, v) {
+		v.depth++
+		v.walkExpression(definition.GetExpression())
+		v.depth--
+		v.visitor.PostDefinition(definition, v)
+	}
+	v.definition = enclosing
+}
+
+func (v *walker_) walkDocument(document DocumentLike) {
+	if document == nil || !v.visitor.PreDocument(document, v) {
+		return
+	}
+	v.depth++
+	v.walkGrammar(document.GetGrammar())
+	v.depth--
+	v.visitor.PostDocument(document, v)
+}
+
+func (v *walker_) walkElement(element ElementLike) {
+	if element == nil || !v.visitor.PreElement(element, v) {
+		return
+	}
+	v.visitor.PostElement(element, v)
+}
+
+func (v *walker_) walkExpression(expression ExpressionLike) {
+	if expression == nil || !v.visitor.PreExpression(expression, v) {
+		return
+	}
+	v.depth++
+	var alternatives = expression.GetAlternatives()
+	if alternatives != nil {
+		var iterator = alternatives.GetIterator()
+		for iterator.HasNext() {
+			var alternative = iterator.GetNext()
+			v.walkAlternative(alternative)
+		}
+	}
+	v.depth--
+	v.visitor.PostExpression(expression, v)
+}
+
+func (v *walker_) walkFactor(factor FactorLike) {
+	if factor == nil || !v.visitor.PreFactor(factor, v) {
+		return
+	}
+	v.depth++
+	v.walkPredicate(factor.GetPredicate())
+	v.walkCardinality(factor.GetCardinality())
+	v.depth--
+	v.visitor.PostFactor(factor, v)
+}
+
+func (v *walker_) walkGlyph(glyph GlyphLike) {
+	if glyph == nil || !v.visitor.PreGlyph(glyph, v) {
+		return
+	}
+	v.visitor.PostGlyph(glyph, v)
+}
+
+func (v *walker_) walkGrammar(grammar GrammarLike) {
+	if grammar == nil || !v.visitor.PreGrammar(grammar, v) {
+		return
+	}
+	v.depth++
+	var statements = grammar.GetStatements()
+	if statements != nil {
+		var iterator = statements.GetIterator()
+		for iterator.HasNext() {
+			var statement = iterator.GetNext()
+			v.walkStatement(statement)
+		}
+	}
+	v.depth--
+	v.visitor.PostGrammar(grammar, v)
+}
+
+func (v *walker_) walkPrecedence(precedence PrecedenceLike) {
+	if precedence == nil || !v.visitor.PrePrecedence(precedence, v) {
+		return
+	}
+	v.depth++
+	v.walkExpression(precedence.GetExpression())
+	v.depth--
+	v.visitor.PostPrecedence(precedence, v)
+}
+
+func (v *walker_) walkPredicate(predicate PredicateLike) {
+	if predicate == nil || !v.visitor.PrePredicate(predicate, v) {
+		return
+	}
+	v.depth++
+	v.walkAssertion(predicate.GetAssertion())
+	v.depth--
+	v.visitor.PostPredicate(predicate, v)
+}
+
+func (v *walker_) walkStatement(statement StatementLike) {
+	if statement == nil || !v.visitor.PreStatement(statement, v) {
+		return
+	}
+	v.depth++
+	v.walkDefinition(statement.GetDefinition())
+	v.depth--
+	v.visitor.PostStatement(statement, v)
+}